@@ -2,6 +2,8 @@
 package builder
 
 import (
+	"fmt"
+	"path"
 	"sort"
 	"sync"
 
@@ -10,23 +12,41 @@ import (
 	"github.com/verless/verless/tree"
 )
 
+const (
+	// sortByKey is the front matter key of custom list pages for
+	// overriding the field the pages are sorted by.
+	sortByKey string = "SortBy"
+	// sortOrderKey is the front matter key of custom list pages for
+	// overriding the sort order.
+	sortOrderKey string = "SortOrder"
+)
+
 // New creates a new builder instance.
 func New(cfg *config.Config) *builder {
+	return NewLanguage(cfg, model.Language{})
+}
+
+// NewLanguage creates a new builder instance for the site of the given
+// language. The routes of all pages are relative to the language, while
+// the routes of the list pages start with the language's Href.
+func NewLanguage(cfg *config.Config, language model.Language) *builder {
 	b := builder{
-		site:  model.NewSite(),
-		cfg:   cfg,
-		mutex: &sync.Mutex{},
-		cache: make(map[string]*model.Node),
+		site:     model.NewSite(),
+		cfg:      cfg,
+		language: language,
+		mutex:    &sync.Mutex{},
+		cache:    make(map[string]*model.Node),
 	}
 	return &b
 }
 
 // builder represents a model builder maintaining a site model.
 type builder struct {
-	site  model.Site
-	cfg   *config.Config
-	mutex *sync.Mutex
-	cache map[string]*model.Node
+	site     model.Site
+	cfg      *config.Config
+	language model.Language
+	mutex    *sync.Mutex
+	cache    map[string]*model.Node
 }
 
 // RegisterPage registers a given page under a given route. It
@@ -71,25 +91,143 @@ func (b *builder) Dispatch() (model.Site, error) {
 	b.site.Meta = b.cfg.Site.Meta
 	b.site.Nav = b.cfg.Site.Nav
 	b.site.Footer = b.cfg.Site.Footer
+	b.site.Language = b.language
 
 	// The final tree traversal does some final tasks:
-	//	1. Assign a route to all list pages
-	//	2. Sort the pages in all list pages by date
-	_ = tree.Walk(b.site.Root, func(path string, node tree.Node) error {
+	//	1. Assign a route to all list pages, including the language
+	//	2. Sort the pages in all list pages
+	err := tree.Walk(b.site.Root, func(route string, node tree.Node) error {
 		n := node.(*model.Node)
 
-		n.ListPage.Route = path
+		n.ListPage.Route = path.Join(b.language.Href, route)
+
+		sortBy, err := b.sortOption(&n.ListPage, sortByKey, b.cfg.Sorting.SortBy)
+		if err != nil {
+			return err
+		}
+
+		sortOrder, err := b.sortOption(&n.ListPage, sortOrderKey, b.cfg.Sorting.SortOrder)
+		if err != nil {
+			return err
+		}
 
-		sort.Slice(n.ListPage.Pages, func(i, j int) bool {
-			return n.ListPage.Pages[i].Date.After(n.ListPage.Pages[j].Date)
-		})
+		if err := model.SortPages(n.ListPage.Pages, sortBy, sortOrder); err != nil {
+			return listPageError(&n.ListPage, err)
+		}
 
 		return nil
 	}, -1)
 
+	if err != nil {
+		return b.site, err
+	}
+
+	redirects, err := b.redirects()
+	if err != nil {
+		return b.site, err
+	}
+	b.site.Redirects = redirects
+
 	return b.site, nil
 }
 
+// redirects creates a redirect for each alias of all pages and custom
+// list pages. An alias must neither be used twice nor be the URL of an
+// existing page. As permalink patterns may produce the same URL for
+// multiple pages or for a list page, redirects also makes sure that all
+// URLs are unique.
+func (b *builder) redirects() ([]model.Redirect, error) {
+	hrefs := make(map[string]*model.Page)
+	redirects := make([]model.Redirect, 0)
+
+	add := func(page *model.Page, href string) error {
+		if other, exists := hrefs[href]; exists {
+			sources := []string{sourceName(other), sourceName(page)}
+			sort.Strings(sources)
+			return fmt.Errorf("pages %s and %s have the same URL %s", sources[0], sources[1], href)
+		}
+		for _, alias := range page.Aliases {
+			redirects = append(redirects, model.Redirect{From: alias, To: href})
+		}
+		hrefs[href] = page
+		return nil
+	}
+
+	err := tree.Walk(b.site.Root, func(_ string, node tree.Node) error {
+		n := node.(*model.Node)
+
+		if err := add(&n.ListPage.Page, n.ListPage.Route); err != nil {
+			return err
+		}
+
+		for i := range n.Pages {
+			if err := add(&n.Pages[i], n.Pages[i].Href); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, -1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(redirects, func(i, j int) bool {
+		return redirects[i].From < redirects[j].From
+	})
+
+	for i, redirect := range redirects {
+		if _, exists := hrefs[redirect.From]; exists {
+			return nil, fmt.Errorf("alias %s of page %s conflicts with an existing page", redirect.From, redirect.To)
+		}
+		if i > 0 && redirects[i-1].From == redirect.From {
+			return nil, fmt.Errorf("alias %s is used by pages %s and %s", redirect.From, redirects[i-1].To, redirect.To)
+		}
+	}
+
+	return redirects, nil
+}
+
+// sourceName returns the source path of a page for error messages. Pages
+// that haven't been parsed from a file, like generated list pages, are
+// named after their ID instead.
+func sourceName(page *model.Page) string {
+	if page.SourcePath() != "" {
+		return page.SourcePath()
+	}
+	if page.ID == "" {
+		return "list page"
+	}
+	return page.ID
+}
+
+// sortOption returns the sort option stored under the given front matter
+// key of a custom list page. If the list page doesn't provide the option,
+// the globally configured option is returned.
+func (b *builder) sortOption(listPage *model.ListPage, key, global string) (string, error) {
+	val := listPage.Param(key)
+	if val == nil {
+		return global, nil
+	}
+
+	option, ok := val.(string)
+	if !ok {
+		return "", listPageError(listPage, fmt.Errorf("invalid value for key %s: expected string, got %v", key, val))
+	}
+
+	return option, nil
+}
+
+// listPageError prefixes an error with the source file of a custom list
+// page, if there is any.
+func listPageError(listPage *model.ListPage, err error) error {
+	if listPage.SourcePath() == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %w", listPage.SourcePath(), err)
+}
+
 // nodeFromCache loads a node from the cache. If the node isn't
 // registered in the cache yet, nodeFromCache will load it from
 // the route tree first.
@@ -127,3 +127,144 @@ func TestBuilder_Dispatch(t *testing.T) {
 		}, -1)
 	}
 }
+
+// TestBuilder_Dispatch_sorting checks if the pages in list pages are
+// sorted according to the configuration and the list page's front matter.
+func TestBuilder_Dispatch_sorting(t *testing.T) {
+	tests := map[string]struct {
+		sortBy        string
+		sortOrder     string
+		params        map[string]interface{}
+		expected      []string
+		expectedError bool
+	}{
+		"default": {
+			expected: []string{"b", "a", "c"},
+		},
+		"configured": {
+			sortBy:   "title",
+			expected: []string{"a", "b", "c"},
+		},
+		"list page": {
+			sortBy:   "title",
+			params:   map[string]interface{}{"sortby": "weight", "SortOrder": "desc"},
+			expected: []string{"c", "b", "a"},
+		},
+		"invalid field": {
+			params:        map[string]interface{}{"SortBy": "author"},
+			expectedError: true,
+		},
+		"invalid type": {
+			params:        map[string]interface{}{"SortOrder": 1},
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		cfg := config.Config{}
+		cfg.Sorting.SortBy = testCase.sortBy
+		cfg.Sorting.SortOrder = testCase.sortOrder
+
+		builder := New(&cfg)
+		builder.site.Root.ListPage = model.ListPage{
+			Page: model.Page{ID: "index", Params: testCase.params},
+			Pages: []*model.Page{
+				{Href: "/a", Title: "A", Weight: 1, Date: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+				{Href: "/b", Title: "B", Weight: 2, Date: time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)},
+				{Href: "/c", Title: "C", Weight: 3, Date: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+			},
+		}
+
+		site, err := builder.Dispatch()
+		if testCase.expectedError {
+			test.Assert(t, err != nil, "invalid sort options should return an error")
+			continue
+		}
+		test.Ok(t, err)
+
+		hrefs := make([]string, 0)
+		for _, p := range site.Root.ListPage.Pages {
+			hrefs = append(hrefs, strings.TrimPrefix(p.Href, "/"))
+		}
+
+		test.Equals(t, testCase.expected, hrefs)
+	}
+}
+
+// TestBuilder_Dispatch_redirects checks if the builder creates a redirect
+// for each page alias and detects conflicting aliases.
+func TestBuilder_Dispatch_redirects(t *testing.T) {
+	tests := map[string]struct {
+		pages         []model.Page
+		expected      []model.Redirect
+		expectedError bool
+	}{
+		"aliases": {
+			pages: []model.Page{
+				{ID: "new-post", Route: "/blog", Href: "/blog/new-post", Aliases: []string{"/old-post", "/posts/old"}},
+				{ID: "index", Route: "/blog", Aliases: []string{"/posts"}},
+			},
+			expected: []model.Redirect{
+				{From: "/old-post", To: "/blog/new-post"},
+				{From: "/posts", To: "/blog"},
+				{From: "/posts/old", To: "/blog/new-post"},
+			},
+		},
+		"alias used twice": {
+			pages: []model.Page{
+				{ID: "a", Route: "/", Href: "/a", Aliases: []string{"/old"}},
+				{ID: "b", Route: "/", Href: "/b", Aliases: []string{"/old"}},
+			},
+			expectedError: true,
+		},
+		"alias of an existing page": {
+			pages: []model.Page{
+				{ID: "a", Route: "/", Href: "/a", Aliases: []string{"/b"}},
+				{ID: "b", Route: "/", Href: "/b"},
+			},
+			expectedError: true,
+		},
+		"pages with the same URL": {
+			pages: []model.Page{
+				{ID: "a", Route: "/blog", Href: "/blog/2020/a"},
+				{ID: "a", Route: "/blog/2020", Href: "/blog/2020/a"},
+			},
+			expectedError: true,
+		},
+		"page with the URL of a list page": {
+			pages: []model.Page{
+				{ID: "blog", Route: "/", Href: "/blog"},
+				{ID: "a", Route: "/blog", Href: "/blog/a"},
+			},
+			expectedError: true,
+		},
+		"page with the URL of a custom list page": {
+			pages: []model.Page{
+				{ID: "index", Route: "/blog"},
+				{ID: "a", Route: "/blog/a", Href: "/blog"},
+			},
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		builder := New(&config.Config{})
+
+		for _, page := range testCase.pages {
+			test.Ok(t, builder.RegisterPage(page))
+		}
+
+		site, err := builder.Dispatch()
+		if testCase.expectedError {
+			test.Assert(t, err != nil, "conflicting aliases or URLs should return an error")
+			continue
+		}
+		test.Ok(t, err)
+
+		test.Equals(t, testCase.expected, site.Redirects)
+	}
+}
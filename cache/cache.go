@@ -0,0 +1,409 @@
+// Package cache provides a persistent build cache that allows verless
+// to skip parsing and rendering content that didn't change.
+//
+// The cache consists of two parts: Parsed pages keyed by the hash of
+// their source file and output files keyed by the hash of all inputs
+// that went into them. Both parts are only valid as long as the cache
+// key - typically a hash of the project and theme configuration - is
+// identical to the key the cache has been saved with.
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/spf13/afero"
+	"github.com/verless/verless/config"
+	"github.com/verless/verless/model"
+)
+
+const (
+	// filename is the name of the cache file inside the cache directory.
+	filename string = "cache.gob"
+	// format is the version of the cache file layout. It has to be
+	// incremented whenever the persisted page data changes.
+	format int = 8
+)
+
+func init() {
+	// Front matter values are stored as interfaces. The cache is encoded
+	// using gob instead of JSON, so that their types are retained and an
+	// integer doesn't turn into a float64, for example.
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+}
+
+// state is the persisted part of the cache.
+type state struct {
+	Version string
+	Format  int
+	Key     string
+	Sources map[string]source
+	Outputs map[string]string
+}
+
+// source represents a parsed content file.
+type source struct {
+	Hash string
+	Page page
+}
+
+// page wraps a model.Page so that the user-provided values stored in
+// unexported fields survive the serialization.
+type page struct {
+	model.Page
+	ProvidedRelated []string
+	ProvidedType    string
+}
+
+// Stats represents the number of pages that have been reused from the
+// cache and the number of pages that had to be rebuilt.
+type Stats struct {
+	Reused  int
+	Rebuilt int
+}
+
+// Cache is a build cache that remembers the parsed pages and rendered
+// output files of the previous build. All methods are safe for concurrent
+// usage.
+type Cache struct {
+	fs     afero.Fs
+	path   string
+	prev   state
+	next   state
+	stats  Stats
+	loaded bool
+	mutex  sync.Mutex
+}
+
+// Path returns the path of the cache file for the project in path.
+func Path(path string) string {
+	return filepath.Join(path, config.CacheDir, filename)
+}
+
+// New creates an empty cache for the project in path that will be saved
+// to the given filesystem.
+func New(fs afero.Fs, path, key string) *Cache {
+	c := Cache{
+		fs:   fs,
+		path: Path(path),
+		prev: newState(key),
+		next: newState(key),
+	}
+	return &c
+}
+
+// Load reads the cache file of the project in path from the given
+// filesystem. If the cache file doesn't exist, can't be read or has
+// been saved with another key or verless version, Load returns an
+// empty cache.
+func Load(fs afero.Fs, path, key string) *Cache {
+	c := New(fs, path, key)
+
+	data, err := afero.ReadFile(fs, c.path)
+	if err != nil {
+		return c
+	}
+
+	var prev state
+
+	if err := decode(data, &prev); err != nil {
+		return c
+	}
+
+	if prev.Version == config.GitTag && prev.Format == format && prev.Key == key {
+		c.prev = prev
+		c.loaded = true
+	}
+
+	return c
+}
+
+// Loaded indicates whether the state of a previous build has been loaded.
+// If it hasn't, the outputs of the previous build are unknown and can't
+// be pruned selectively.
+func (c *Cache) Loaded() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.loaded
+}
+
+// Page returns the cached page for the given source file if the file
+// hash matches the hash the page has been cached with.
+func (c *Cache) Page(file, hash string) (model.Page, bool) {
+	p, cachedHash, ok := c.Source(file)
+	if !ok || cachedHash != hash {
+		return model.Page{}, false
+	}
+	return p, true
+}
+
+// Source returns the cached page for the given source file along with
+// the hash of the file, regardless of the file's current content. Only
+// use Source if you know that the file hasn't changed.
+func (c *Cache) Source(file string) (model.Page, string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	src, ok := c.prev.Sources[file]
+	if !ok {
+		return model.Page{}, "", false
+	}
+
+	p := src.Page.Page
+
+	for _, related := range src.Page.ProvidedRelated {
+		p.AddProvidedRelated(related)
+	}
+	p.SetProvidedType(src.Page.ProvidedType)
+
+	return p, src.Hash, true
+}
+
+// StorePage stores a freshly parsed or reused page for the next build.
+// Source files that aren't stored again won't be part of the next build.
+func (c *Cache) StorePage(file, hash string, p model.Page) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.next.Sources[file] = source{
+		Hash: hash,
+		Page: page{
+			Page:            p,
+			ProvidedRelated: p.ProvidedRelated(),
+			ProvidedType:    p.ProvidedType(),
+		},
+	}
+}
+
+// IsFresh indicates whether the given output file has been rendered with
+// the given key in the previous build.
+func (c *Cache) IsFresh(output, key string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	prevKey, ok := c.prev.Outputs[output]
+	return ok && key != "" && prevKey == key
+}
+
+// StoreOutput stores the key of an output file for the next build and
+// records whether the output has been reused or rebuilt.
+func (c *Cache) StoreOutput(output, key string, reused bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.next.Outputs[output] = key
+
+	if reused {
+		c.stats.Reused++
+	} else {
+		c.stats.Rebuilt++
+	}
+}
+
+// StoreFile stores an output file that isn't subject to freshness checks,
+// for example a copied static file, for the next build. Unlike StoreOutput,
+// it doesn't affect the statistics or the key of an already stored output.
+func (c *Cache) StoreFile(output string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, exists := c.next.Outputs[output]; !exists {
+		c.next.Outputs[output] = ""
+	}
+}
+
+// StoreCopy stores an output file that has been copied from a source file
+// or is still fresh, along with the hash of the source file. Unlike
+// StoreOutput, it doesn't affect the statistics, which only count pages.
+func (c *Cache) StoreCopy(output, hash string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.next.Outputs[output] = hash
+}
+
+// RemoveOutput removes an output file from the cache, so that it will be
+// rendered again by the next build. This is required if rendering the
+// output has failed.
+func (c *Cache) RemoveOutput(output string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.prev.Outputs, output)
+	delete(c.next.Outputs, output)
+}
+
+// Stale returns all output files of the previous build that haven't
+// been stored for the next build. Those files can be pruned safely.
+func (c *Cache) Stale() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stale := make([]string, 0)
+
+	for output := range c.prev.Outputs {
+		if _, exists := c.next.Outputs[output]; !exists {
+			stale = append(stale, output)
+		}
+	}
+
+	sort.Strings(stale)
+
+	return stale
+}
+
+// Stats returns the number of reused and rebuilt pages since the cache
+// has been created or saved the last time.
+func (c *Cache) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.stats
+}
+
+// ReuseOutput stores an output file that doesn't have to be rendered
+// again, along with the key it has been rendered with in the previous
+// build, and records it as reused.
+func (c *Cache) ReuseOutput(output string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.next.Outputs[output] = c.prev.Outputs[output]
+	c.stats.Reused++
+}
+
+// Fail merges the outputs that have been written by a failed build into
+// the previous state and makes it the state for the next build, so that
+// those outputs aren't considered fresh with the keys of their previous
+// content by the next build.
+func (c *Cache) Fail() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.prev.Outputs == nil {
+		c.prev.Outputs = make(map[string]string)
+	}
+	for output, key := range c.next.Outputs {
+		c.prev.Outputs[output] = key
+	}
+	c.next.Outputs = c.prev.Outputs
+	c.next.Sources = c.prev.Sources
+}
+
+// Save writes all pages and outputs stored for the next build to the
+// cache file and commits them.
+func (c *Cache) Save() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	data, err := encode(c.next)
+	if err != nil {
+		return err
+	}
+
+	if err := c.fs.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+
+	if err := afero.WriteFile(c.fs, c.path, data, 0644); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// Commit makes all pages and outputs stored for the next build available
+// to the next build without writing them to the cache file. Afterwards,
+// the statistics are reset.
+func (c *Cache) Commit() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	data, err := encode(c.next)
+	if err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// commit replaces the previous state with the given serialized state.
+// Decoding the state instead of keeping the stored pages ensures that
+// the next build gets the same pages as if the cache had been loaded.
+func (c *Cache) commit(data []byte) error {
+	var prev state
+
+	if err := decode(data, &prev); err != nil {
+		return err
+	}
+
+	c.prev = prev
+	c.next = newState(c.prev.Key)
+	c.stats = Stats{}
+	c.loaded = true
+
+	return nil
+}
+
+// Hash returns the hex-encoded SHA-256 hash of all given byte slices.
+func Hash(data ...[]byte) string {
+	h := sha256.New()
+	for _, d := range data {
+		// Prefixing each slice with its length prevents that different
+		// slices with the same concatenation result in the same hash.
+		_, _ = h.Write([]byte(strconv.Itoa(len(d)) + ":"))
+		_, _ = h.Write(d)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HashValues returns the hash of the JSON representation of all given
+// values. This is useful for hashing configurations.
+func HashValues(values ...interface{}) (string, error) {
+	data := make([][]byte, 0, len(values))
+
+	for _, v := range values {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		data = append(data, b)
+	}
+
+	return Hash(data...), nil
+}
+
+// encode encodes the given state using gob.
+func encode(s state) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := gob.NewEncoder(&buf).Encode(s); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decode decodes a gob-encoded state into s.
+func decode(data []byte, s *state) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(s)
+}
+
+func newState(key string) state {
+	return state{
+		Version: config.GitTag,
+		Format:  format,
+		Key:     key,
+		Sources: make(map[string]source),
+		Outputs: make(map[string]string),
+	}
+}
@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/verless/verless/model"
+	"github.com/verless/verless/test"
+)
+
+const (
+	projectPath = "/project"
+	testKey     = "test key"
+)
+
+// TestCache_Page checks if a stored page can be loaded from a saved
+// cache as long as the source hash and the cache key match.
+func TestCache_Page(t *testing.T) {
+	tests := map[string]struct {
+		hash   string
+		key    string
+		cached bool
+	}{
+		"same hash and key": {
+			hash:   "hash",
+			key:    testKey,
+			cached: true,
+		},
+		"changed hash": {
+			hash: "other hash",
+			key:  testKey,
+		},
+		"changed key": {
+			hash: "hash",
+			key:  "other key",
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		memMapFs := afero.NewMemMapFs()
+
+		page := model.Page{
+			Title: "Making Espresso",
+			Params: map[string]interface{}{
+				"Shots":  2,
+				"Ratio":  1.5,
+				"Grind":  map[string]interface{}{"Level": 7},
+				"Beans":  []interface{}{"Arabica", 100},
+				"Origin": nil,
+			},
+		}
+		page.AddProvidedRelated("/blog/steaming-milk")
+		page.SetProvidedType("post")
+
+		c := New(memMapFs, projectPath, testKey)
+		c.StorePage("/blog/making-espresso.md", "hash", page)
+		test.Ok(t, c.Save())
+
+		c = Load(memMapFs, projectPath, testCase.key)
+		p, cached := c.Page("/blog/making-espresso.md", testCase.hash)
+
+		test.Equals(t, testCase.cached, cached)
+		if !cached {
+			continue
+		}
+
+		test.Equals(t, page.Title, p.Title)
+		test.Equals(t, page.Params, p.Params)
+		test.Equals(t, page.ProvidedRelated(), p.ProvidedRelated())
+		test.Equals(t, page.ProvidedType(), p.ProvidedType())
+	}
+}
+
+// TestCache_Stale checks if outputs of the previous build that haven't
+// been stored again are returned as stale outputs.
+func TestCache_Stale(t *testing.T) {
+	memMapFs := afero.NewMemMapFs()
+
+	c := New(memMapFs, projectPath, testKey)
+	c.StoreOutput("/target/blog/index.html", "a", false)
+	c.StoreOutput("/target/blog/espresso/index.html", "b", false)
+	test.Ok(t, c.Save())
+
+	c = Load(memMapFs, projectPath, testKey)
+	test.Equals(t, true, c.IsFresh("/target/blog/index.html", "a"))
+	test.Equals(t, false, c.IsFresh("/target/blog/index.html", "c"))
+
+	c.StoreOutput("/target/blog/index.html", "a", true)
+
+	test.Equals(t, []string{"/target/blog/espresso/index.html"}, c.Stale())
+	test.Equals(t, Stats{Reused: 1}, c.Stats())
+}
+
+// TestCache_Fail checks if outputs written by a failed build are
+// stored with their new keys and if failed outputs are rendered again.
+func TestCache_Fail(t *testing.T) {
+	memMapFs := afero.NewMemMapFs()
+
+	c := New(memMapFs, projectPath, testKey)
+	c.StoreOutput("/target/blog/index.html", "a", false)
+	c.StoreOutput("/target/about/index.html", "b", false)
+	test.Ok(t, c.Save())
+
+	c = Load(memMapFs, projectPath, testKey)
+	c.StoreOutput("/target/blog/index.html", "c", false)
+	c.RemoveOutput("/target/about/index.html")
+	c.Fail()
+	test.Ok(t, c.Save())
+
+	c = Load(memMapFs, projectPath, testKey)
+	test.Equals(t, false, c.IsFresh("/target/blog/index.html", "a"))
+	test.Equals(t, true, c.IsFresh("/target/blog/index.html", "c"))
+	test.Equals(t, false, c.IsFresh("/target/about/index.html", "b"))
+}
+
+// TestCache_ReuseOutput checks if a reused output keeps the key it has
+// been rendered with in the previous build.
+func TestCache_ReuseOutput(t *testing.T) {
+	memMapFs := afero.NewMemMapFs()
+
+	c := New(memMapFs, projectPath, testKey)
+	c.StoreOutput("/target/blog/index.html", "a", false)
+	test.Ok(t, c.Commit())
+
+	c.ReuseOutput("/target/blog/index.html")
+	test.Equals(t, Stats{Reused: 1}, c.Stats())
+	test.Ok(t, c.Commit())
+
+	test.Equals(t, true, c.IsFresh("/target/blog/index.html", "a"))
+}
+
+// TestHash checks if different inputs result in different hashes.
+func TestHash(t *testing.T) {
+	test.Equals(t, Hash([]byte("ab")), Hash([]byte("ab")))
+	test.NotEquals(t, Hash([]byte("ab"), []byte("c")), Hash([]byte("a"), []byte("bc")))
+}
@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// recordingFs is a filesystem that stores each file created or opened
+// for writing as an output of the current build.
+type recordingFs struct {
+	afero.Fs
+	cache *Cache
+}
+
+// Fs returns a filesystem that writes to fs and records all written
+// files as outputs of the current build. This ensures that files that
+// aren't rendered by the writer - static files or plugin outputs, for
+// example - will be pruned once they aren't written anymore.
+func (c *Cache) Fs(fs afero.Fs) afero.Fs {
+	return &recordingFs{
+		Fs:    fs,
+		cache: c,
+	}
+}
+
+// Create records the file and creates it using the underlying filesystem.
+func (r *recordingFs) Create(name string) (afero.File, error) {
+	r.cache.StoreFile(filepath.Clean(name))
+	return r.Fs.Create(name)
+}
+
+// OpenFile records the file if it is opened for writing and opens it
+// using the underlying filesystem.
+func (r *recordingFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE) != 0 {
+		r.cache.StoreFile(filepath.Clean(name))
+	}
+	return r.Fs.OpenFile(name, flag, perm)
+}
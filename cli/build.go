@@ -4,6 +4,8 @@ import (
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/verless/verless/core"
+	"github.com/verless/verless/out"
+	"github.com/verless/verless/out/style"
 )
 
 // newBuildCmd creates the `verless build` command.
@@ -27,7 +29,15 @@ func newBuildCmd() *cobra.Command {
 				return err
 			}
 
-			return build.Run()
+			if err := build.Run(); err != nil {
+				return err
+			}
+
+			stats := build.Stats()
+			out.T(style.HeavyCheckMark, "project built successfully (%d pages rebuilt, %d reused)",
+				stats.Rebuilt, stats.Reused)
+
+			return nil
 		},
 	}
 
@@ -40,6 +50,21 @@ func addBuildOptions(buildCmd *cobra.Command, options *core.BuildOptions, addOve
 	buildCmd.Flags().StringVarP(&options.OutputDir, "output", "o",
 		"", `specify an output directory`)
 
+	buildCmd.Flags().BoolVar(&options.DisableCache, "no-cache",
+		false, `parse and render all pages without using the build cache`)
+
+	buildCmd.Flags().BoolVar(&options.Drafts, "drafts",
+		false, `include pages marked as draft`)
+
+	buildCmd.Flags().BoolVar(&options.Future, "future",
+		false, `include pages with a publish date in the future`)
+
+	buildCmd.Flags().BoolVar(&options.Expired, "expired",
+		false, `include pages with an expiry date in the past`)
+
+	buildCmd.Flags().BoolVar(&options.DebugTemplates, "debug-templates",
+		false, `print the template used for each output file`)
+
 	if addOverwrite {
 		// Overwrite should not have a shorthand to avoid accidental usage.
 		buildCmd.Flags().BoolVar(&options.Overwrite, "overwrite",
@@ -9,20 +9,152 @@ import (
 // Config represents the user configuration stored in verless.yml.
 type Config struct {
 	Version string
-	Site    struct {
-		Meta   model.Meta
-		Nav    model.Nav
-		Footer model.Footer
-	}
+	Site    Site
 	Plugins []string
 	Theme   string
 	Types   map[string]*model.Type
-	Build   struct {
+	// DefaultLanguage is the code of the language whose pages are served
+	// from the website root.
+	DefaultLanguage string
+	// Languages declares all languages of a multilingual website, keyed
+	// by their code like de.
+	Languages map[string]Language
+	// Taxonomies declares all taxonomies like tags or categories, keyed
+	// by their name.
+	Taxonomies map[string]Taxonomy
+	// Permalinks maps content directories like blog to permalink patterns
+	// like /blog/:year/:month/:slug.
+	Permalinks map[string]string
+	Pagination struct {
+		// PageSize is the maximum number of pages per list page. 0
+		// disables pagination.
+		PageSize int
+	}
+	// Feeds configures the feeds generated by the atom plugin.
+	Feeds     Feeds
+	Redirects struct {
+		// Formats contains the formats of the redirect files to write
+		// for page aliases: netlify and nginx.
+		Formats []string
+	}
+	Search struct {
+		// Sections indicates whether the search index should be split
+		// into one shard per top-level content directory.
+		Sections bool
+	}
+	Sorting struct {
+		// SortBy is the field list pages are sorted by: date, weight or
+		// title. Defaults to date.
+		SortBy string
+		// SortOrder is either asc or desc. Defaults to desc for dates
+		// and asc for all other fields.
+		SortOrder string
+	}
+	Templates struct {
+		// Compat renders all templates using text/template instead of
+		// html/template, so that values aren't escaped. It keeps themes
+		// working that haven't been migrated yet.
+		Compat bool
+	}
+	Build struct {
 		Overwrite bool
 		Before    []string
 	}
 }
 
+// Site represents the global website data.
+type Site struct {
+	Meta   model.Meta
+	Nav    model.Nav
+	Footer model.Footer
+}
+
+// Language represents the configuration of a language.
+type Language struct {
+	// Name is the display name of the language like Deutsch. Defaults
+	// to the language code.
+	Name string
+	// ContentDir is a directory inside the content directory that only
+	// contains pages in this language, like de. Pages in other content
+	// directories can be assigned to the language using a filename
+	// suffix like about.de.md.
+	ContentDir string
+	// Weight determines the order of the languages. Languages with a
+	// lower weight come first.
+	Weight int
+	// Site overrides the global website data. Only the provided values
+	// are overridden.
+	Site Site
+}
+
+// Taxonomy represents the configuration of a taxonomy.
+type Taxonomy struct {
+	// Key is the front matter key holding the terms of a page. It is
+	// case-insensitive and defaults to the taxonomy name.
+	Key string
+	// Base is the URL base for all terms. Defaults to /<name>.
+	Base string
+	// Template is the template for the list page of each term. Defaults
+	// to the taxonomy template or the list page template.
+	Template string
+	// TermsTemplate is the template for the page listing all terms.
+	// Defaults to the list page template.
+	TermsTemplate string
+	// SortBy is the field the pages of each term are sorted by. Defaults
+	// to the global sorting configuration.
+	SortBy string
+	// SortOrder is the order the pages of each term are sorted in.
+	// Defaults to the global sorting configuration.
+	SortOrder string
+}
+
+// Feeds represents the configuration of the generated feeds.
+type Feeds struct {
+	// Formats contains the feed formats to generate: atom, rss and json.
+	// Defaults to atom.
+	Formats []string
+	// Content indicates whether the full content of each page should be
+	// included in the feeds.
+	Content bool
+	// Sections indicates whether a feed should be generated for each
+	// content directory in addition to the site-wide feed.
+	Sections bool
+	// Taxonomies contains the taxonomies, like tags, for whose terms a
+	// feed should be generated.
+	Taxonomies []string
+}
+
+// ForLanguage returns a copy of the configuration where the global website
+// data is overridden by the website data of the language with the given
+// code. Values that haven't been provided by the language are kept.
+func (c Config) ForLanguage(code string) Config {
+	language, ok := c.Languages[code]
+	if !ok {
+		return c
+	}
+
+	override := func(value *string, with string) {
+		if with != "" {
+			*value = with
+		}
+	}
+
+	override(&c.Site.Meta.Title, language.Site.Meta.Title)
+	override(&c.Site.Meta.Subtitle, language.Site.Meta.Subtitle)
+	override(&c.Site.Meta.Description, language.Site.Meta.Description)
+	override(&c.Site.Meta.Author, language.Site.Meta.Author)
+	override(&c.Site.Meta.Base, language.Site.Meta.Base)
+
+	if len(language.Site.Nav.Items) > 0 {
+		c.Site.Nav = language.Site.Nav
+	}
+	if len(language.Site.Footer.Items) > 0 {
+		c.Site.Footer = language.Site.Footer
+	}
+
+	return c
+}
+
 // FromFile looks for a configuration file and converts it to a Config.
 func FromFile(path, filename string) (Config, error) {
 	viper.AddConfigPath(path)
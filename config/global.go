@@ -22,9 +22,19 @@ const (
 	// The directory can exist in each theme directory and in the StaticDir.
 	GeneratedDir string = "generated"
 
+	// DataDir is the directory for global data files.
+	DataDir string = "data"
+
+	// I18nDir is the directory for the translated strings of each
+	// language, like i18n/de.yml.
+	I18nDir string = "i18n"
+
 	// StaticDir is the directory for static files.
 	StaticDir string = "static"
 
 	// OutputDir is the default output directory.
 	OutputDir string = "target"
+
+	// CacheDir is the directory for the persistent build cache.
+	CacheDir string = ".verless"
 )
@@ -5,18 +5,24 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
-	"github.com/verless/verless/builder"
+	"github.com/verless/verless/cache"
 	"github.com/verless/verless/config"
+	"github.com/verless/verless/data"
 	"github.com/verless/verless/fs"
+	"github.com/verless/verless/graph"
 	"github.com/verless/verless/model"
 	"github.com/verless/verless/parser"
 	"github.com/verless/verless/plugin"
+	"github.com/verless/verless/taxonomy"
 	"github.com/verless/verless/theme"
 	"github.com/verless/verless/writer"
 )
@@ -37,7 +43,7 @@ Consider using the --overwrite flag or enable build.overwrite in verless.yml`)
 	ErrMissingVersionKey = errors.New("missing `version` key in verless.yml")
 )
 
-// Parser represents a parser that processes Markdown files and converts
+// Parser represents a parser that processes content files and converts
 // them into a model instance.
 type Parser interface {
 	// ParsePage must be safe for concurrent usage.
@@ -53,9 +59,23 @@ type Builder interface {
 }
 
 // Writer represents a model writer that renders the site model as HTML
-// using the corresponding templates.
+// using the corresponding templates. Multilingual websites consist of a
+// site model for each language.
 type Writer interface {
-	Write(site model.Site) error
+	Write(sites ...model.Site) error
+	// Prune removes output files of the previous build that haven't been
+	// written again. It is called after all plugins have written their
+	// files.
+	Prune() error
+}
+
+// PartialWriter is a Writer that is able to render only the outputs that
+// are affected by changed files in partial builds.
+type PartialWriter interface {
+	Writer
+	// WriteChanged renders all outputs that depend on one of the changed
+	// files as well as outputs that don't exist yet.
+	WriteChanged(changed map[string]bool, sites ...model.Site) error
 }
 
 // BuildOptions represents options for running a verless build.
@@ -67,17 +87,69 @@ type BuildOptions struct {
 	Overwrite bool
 	// RecompileTemplates forces a recompilation of all templates.
 	RecompileTemplates bool
+	// DisableCache disables the persistent build cache, so that all
+	// pages will be parsed and rendered again.
+	DisableCache bool
+	// Drafts includes pages that are marked as draft.
+	Drafts bool
+	// Future includes pages with a publish date in the future.
+	Future bool
+	// Expired includes pages with an expiry date in the past.
+	Expired bool
+	// DebugTemplates prints the template used for each output file.
+	DebugTemplates bool
 }
 
 // Build provides methods for building a static site.
 type Build struct {
-	Path    string
-	Parser  Parser
+	Path string
+	// Parsers maps file extensions like .md to the parser for content
+	// files with that extension. Files without a parser are ignored.
+	Parsers map[string]Parser
+	// Builder maintains the site model of the default language.
 	Builder Builder
 	Writer  Writer
 	Plugins []plugin.Plugin
-	Types   map[string]*model.Type
-	Options BuildOptions
+	// Taxonomies groups the pages of the default language by their
+	// terms, like tags.
+	Taxonomies *taxonomy.Taxonomies
+	Types      map[string]*model.Type
+	Options    BuildOptions
+	Cache      *cache.Cache
+	// Graph links all project files to the outputs depending on them.
+	// It is populated while running the build.
+	Graph *graph.Graph
+
+	stats cache.Stats
+	// bundles contains all leaf bundles keyed by their index file.
+	bundles map[string]*bundle
+	// now is the point in time used for deciding whether a page has
+	// been published or has expired.
+	now time.Time
+	// redirects contains the redirects for all page aliases of the last
+	// successful run.
+	redirects []model.Redirect
+	// permalinks contains the permalink patterns for all sections.
+	permalinks permalinks
+	// languages contains all languages of the website, the default
+	// language first. Each language has its own builder and taxonomies.
+	languages []*language
+	// changed contains the absolute paths of all changed content files
+	// for partial builds. If changed is nil, all files are considered
+	// as changed.
+	changed map[string]bool
+	// cfg is the project configuration the build has been created with.
+	cfg config.Config
+	// plugins contains the factories of all configured plugins.
+	plugins []func() plugin.Plugin
+	// funcs contains the template functions of the current plugin
+	// instances. It is shared with the writer.
+	funcs map[string]interface{}
+	// ran indicates whether the build has been run before.
+	ran bool
+	// sources maps the content path of each page to its source file.
+	sources      map[string]string
+	sourcesMutex sync.Mutex
 }
 
 // New initializes a new Build instance.
@@ -97,37 +169,79 @@ func NewBuild(targetFs afero.Fs, path string, options BuildOptions) (*Build, err
 		return nil, ErrCannotOverwrite
 	}
 
+	themeCfg, err := theme.GetConfig(path, cfg.Theme)
+	if err != nil {
+		return nil, err
+	}
+
+	buildCache, err := loadCache(targetFs, path, outputDir, &cfg, &themeCfg, options.DisableCache)
+	if err != nil {
+		return nil, err
+	}
+
+	// All files written to the output directory are recorded in the cache,
+	// so that they can be pruned once they aren't written anymore.
+	outputFs := buildCache.Fs(targetFs)
+
+	depGraph := graph.New()
+
+	if err := addConfigFiles(depGraph, path); err != nil {
+		return nil, err
+	}
+
 	writerCtx := writer.Context{
-		Fs:                 targetFs,
+		Fs:                 outputFs,
 		Path:               path,
 		OutputDir:          outputDir,
 		Theme:              cfg.Theme,
 		RecompileTemplates: options.RecompileTemplates,
-	}
-
-	themeCfg, err := theme.GetConfig(path, cfg.Theme)
-	if err != nil {
-		return nil, err
+		CompatTemplates:    cfg.Templates.Compat,
+		DebugTemplates:     options.DebugTemplates,
+		PageSize:           cfg.Pagination.PageSize,
+		RedirectFormats:    cfg.Redirects.Formats,
+		Cache:              buildCache,
+		Graph:              depGraph,
+		Funcs:              make(map[string]interface{}),
 	}
 
 	b := Build{
 		Path:    path,
-		Parser:  parser.NewMarkdown(),
-		Builder: builder.New(&cfg),
+		Parsers: defaultParsers(),
 		Writer:  writer.New(writerCtx),
 		Types:   theme.GetTypes(&themeCfg, cfg.Types),
 		Options: options,
+		Cache:   buildCache,
+		Graph:   depGraph,
+		cfg:     cfg,
+		funcs:   writerCtx.Funcs,
 	}
 
-	plugins := plugin.LoadAll(&cfg, targetFs, outputDir)
+	plugins := plugin.LoadAll(&cfg, outputFs, outputDir)
+
+	if b.languages, err = newLanguages(&cfg); err != nil {
+		return nil, err
+	}
+
+	b.Builder = b.languages[0].builder
+	b.Taxonomies = b.languages[0].taxonomies
+
+	if b.permalinks, err = newPermalinks(cfg.Permalinks); err != nil {
+		return nil, err
+	}
 
 	for _, key := range cfg.Plugins {
+		// The tags plugin has been replaced by the tags taxonomy.
+		if key == taxonomy.Tags {
+			continue
+		}
 		if _, exists := plugins[key]; !exists {
 			return nil, fmt.Errorf("plugin %s not found", key)
 		}
-		b.Plugins = append(b.Plugins, plugins[key]())
+		b.plugins = append(b.plugins, plugins[key])
 	}
 
+	b.loadPlugins()
+
 	for _, beforeHook := range cfg.Build.Before {
 		cmdParts := strings.Split(beforeHook, " ")
 		cmd := exec.Command(cmdParts[0], cmdParts[1:]...)
@@ -153,21 +267,49 @@ func NewBuild(targetFs afero.Fs, path string, options BuildOptions) (*Build, err
 //	2. Spawn workers reading from that channel.
 //	3. Process each received file:
 //		3.1. Read the file as a []byte
-//		3.2. Parse the []byte and convert it to a model.Page.
+//		3.2. Parse the []byte and convert it to a model.Page, unless
+//		     the page can be loaded from the build cache.
 //		3.3. Register the page in the builder's site model.
 //		3.4. Let each plugin process the page.
-//	4. Get the site model from the builder and render it as a website.
+//	4. Get the site model from the builder, load all data files and render
+//	   it as a website.
 //	5. Let each plugin finish its work, e.g. by writing a file.
+//	6. Save the build cache for the next build.
 func (b *Build) Run() error {
 	var (
 		files           = make(chan string)
 		errorCh         = make(chan error)
-		collectedErrors = make([]error, 0)
+		collectedErrors = make(Errors, 0)
 		contentDir      = filepath.Join(b.Path, config.ContentDir)
 	)
 
+	// The content directory has to be absolute for comparing the files
+	// with the changed files of a partial build.
+	contentDir, err := filepath.Abs(contentDir)
+	if err != nil {
+		return err
+	}
+
+	// A build that is run again, for example for a partial build, starts
+	// with an empty site model.
+	if b.ran {
+		if err := b.reset(); err != nil {
+			return err
+		}
+	}
+
+	b.ran = true
+	b.now = time.Now()
+	b.sources = make(map[string]string)
+
+	if b.bundles, err = findBundles(contentDir, b.hasParser, b.indexLanguage); err != nil {
+		return err
+	}
+
+	b.addChangedBundles(contentDir)
+
 	go func() {
-		if err := fs.StreamFiles(contentDir, files, fs.MarkdownOnly, fs.NoUnderscores); err != nil {
+		if err := fs.StreamFiles(contentDir, files, b.hasParser, fs.NoUnderscores); err != nil {
 			errorCh <- err
 		}
 	}()
@@ -184,7 +326,7 @@ func (b *Build) Run() error {
 			// Process the files received via the files channel.
 			for file := range files {
 				if err := b.processFile(contentDir, file); err != nil {
-					errorCh <- err
+					errorCh <- newFileError(filepath.Join(contentDir, file), err)
 				}
 			}
 			wg.Done()
@@ -208,7 +350,12 @@ func (b *Build) Run() error {
 	}
 
 	if len(collectedErrors) > 0 {
-		return fmt.Errorf("errors while processing files: %v", collectedErrors)
+		// Files are processed in parallel, so the errors are sorted to
+		// report them in a stable order.
+		sort.Slice(collectedErrors, func(i, j int) bool {
+			return collectedErrors[i].Error() < collectedErrors[j].Error()
+		})
+		return collectedErrors
 	}
 
 	if err := b.postProcessing(); err != nil {
@@ -216,25 +363,170 @@ func (b *Build) Run() error {
 	}
 
 	if err := b.render(); err != nil {
+		// Outputs that have already been written must not be reused
+		// with the keys of their previous content.
+		b.Cache.Fail()
+		_ = b.saveCache()
 		return err
 	}
 
+	b.stats = b.Cache.Stats()
+
+	return b.saveCache()
+}
+
+// saveCache saves the build cache for the next build. If the cache is
+// disabled, its state is only kept in memory for running the build again.
+func (b *Build) saveCache() error {
+	if b.Options.DisableCache {
+		return b.Cache.Commit()
+	}
+	return b.Cache.Save()
+}
+
+// reset discards the site models and plugin states of the previous run,
+// so that the build can be run again.
+func (b *Build) reset() error {
+	languages, err := newLanguages(&b.cfg)
+	if err != nil {
+		return err
+	}
+
+	b.languages = languages
+	b.Builder = b.languages[0].builder
+	b.Taxonomies = b.languages[0].taxonomies
+	b.Plugins = nil
+
+	b.loadPlugins()
+
 	return nil
 }
 
+// loadPlugins creates new instances of all configured plugins and
+// registers the template functions they provide for the writer. The
+// functions of previous plugin instances are discarded.
+func (b *Build) loadPlugins() {
+	for _, newPlugin := range b.plugins {
+		b.Plugins = append(b.Plugins, newPlugin())
+	}
+
+	for name := range b.funcs {
+		delete(b.funcs, name)
+	}
+
+	for _, p := range b.Plugins {
+		if funcsPlugin, ok := p.(plugin.FuncsPlugin); ok {
+			for name, fn := range funcsPlugin.Funcs() {
+				b.funcs[name] = fn
+			}
+		}
+	}
+}
+
+// addChangedBundles marks the index files of all leaf bundles containing
+// a changed resource as changed in partial builds, because the index
+// pages list the resources of their bundle.
+func (b *Build) addChangedBundles(contentDir string) {
+	if b.changed == nil {
+		return
+	}
+
+	dirs := make(map[string]bool, len(b.changed))
+	for file := range b.changed {
+		dirs[filepath.Dir(file)] = true
+	}
+
+	for index, bundle := range b.bundles {
+		if dirs[filepath.Join(contentDir, bundle.dir)] {
+			b.changed[filepath.Join(contentDir, index)] = true
+		}
+	}
+}
+
+// RegisterParser registers a parser for content files with the given
+// extension, like .adoc. An existing parser for that extension will be
+// replaced.
+func (b *Build) RegisterParser(extension string, parser Parser) {
+	if b.Parsers == nil {
+		b.Parsers = make(map[string]Parser)
+	}
+	b.Parsers[normalizeExtension(extension)] = parser
+}
+
+// hasParser determines whether there is a parser for the given file.
+func (b *Build) hasParser(file string) bool {
+	_, exists := b.Parsers[strings.ToLower(filepath.Ext(file))]
+	return exists
+}
+
+// Stats returns the number of pages that have been reused from the build
+// cache and the number of pages that have been rebuilt in the last run.
+func (b *Build) Stats() cache.Stats {
+	return b.stats
+}
+
+// Redirects returns the redirects for all page aliases of the last
+// successful run.
+func (b *Build) Redirects() []model.Redirect {
+	return b.redirects
+}
+
+// render finishes the site model of each language and renders all
+// sites at once.
 func (b *Build) render() error {
-	site, err := b.Builder.Dispatch()
+	sites := make([]model.Site, len(b.languages))
+
+	for i, l := range b.languages {
+		site, err := l.builder.Dispatch()
+		if err != nil {
+			return err
+		}
+		sites[i] = site
+	}
+
+	siteData, err := data.Load(filepath.Join(b.Path, config.DataDir))
 	if err != nil {
 		return err
 	}
 
-	for _, p := range b.Plugins {
-		if err := p.PreWrite(&site); err != nil {
+	i18n, err := data.Load(filepath.Join(b.Path, config.I18nDir))
+	if err != nil {
+		return err
+	}
+
+	languages := b.siteLanguages()
+
+	for i, l := range b.languages {
+		sites[i].Data = siteData
+		sites[i].Languages = languages
+
+		if languages != nil {
+			sites[i].I18n = l.translatedStrings(i18n, b.languages[0].Code)
+		}
+
+		if err := l.taxonomies.PreWrite(&sites[i]); err != nil {
 			return err
 		}
 	}
 
-	if err := b.Writer.Write(site); err != nil {
+	// Translations are linked after the term pages have been created, so
+	// that the pages listed by term pages are linked as well.
+	linkTranslations(sites)
+
+	for i := range sites {
+		for _, p := range b.Plugins {
+			if err := p.PreWrite(&sites[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	redirects, err := mergeRedirects(sites)
+	if err != nil {
+		return err
+	}
+
+	if err := b.write(sites); err != nil {
 		return err
 	}
 
@@ -244,9 +536,25 @@ func (b *Build) render() error {
 		}
 	}
 
+	if err := b.Writer.Prune(); err != nil {
+		return err
+	}
+
+	b.redirects = redirects
+
 	return nil
 }
 
+// write renders the sites of all languages. In partial builds, only the
+// outputs affected by the changed files are rendered if the writer is
+// able to do so.
+func (b *Build) write(sites []model.Site) error {
+	if partialWriter, ok := b.Writer.(PartialWriter); ok && b.changed != nil {
+		return partialWriter.WriteChanged(b.changed, sites...)
+	}
+	return b.Writer.Write(sites...)
+}
+
 func (b *Build) preProcessing() error {
 	for _, p := range b.Plugins {
 		prePostPlugin, ok := p.(plugin.PrePostProcessPlugin)
@@ -277,27 +585,70 @@ func (b *Build) postProcessing() error {
 }
 
 func (b *Build) processFile(contentDir, file string) error {
-	src, err := ioutil.ReadFile(filepath.Join(contentDir, file))
+	sourcePath := filepath.Join(contentDir, file)
+
+	page, hash, err := b.loadPage(file, sourcePath)
 	if err != nil {
 		return err
 	}
 
-	page, err := b.Parser.ParsePage(src)
-	if err != nil {
+	b.Cache.StorePage(file, hash, page)
+	page.SetSource(sourcePath, hash)
+
+	if !b.isIncluded(&page) {
+		return nil
+	}
+
+	bundle, isBundle := b.bundles[file]
+
+	// The route of a page is relative to its language. For example, both
+	// /about.md and /about.de.md have / as route.
+	l, languageFile := b.languageOf(file)
+	page.Language = l.Code
+
+	// A directory only becomes a bundle if its index file opts in, so that
+	// existing list pages stored next to images remain list pages. The
+	// content directory of a language is never a bundle.
+	if !page.Bundle || filepath.Dir(languageFile) == string(filepath.Separator) {
+		isBundle = false
+	}
+
+	if isBundle {
+		bundle.apply(&page, languageFile)
+	} else {
+		// A page like /blog/coffee/making-espresso.md will have /blog/coffee as
+		// route and making-espresso as ID.
+		page.Route = filepath.ToSlash(filepath.Dir(languageFile))
+		page.ID = strings.TrimSuffix(filepath.Base(languageFile), filepath.Ext(languageFile))
+		page.Href = filepath.ToSlash(filepath.Join(page.Route, page.ID))
+	}
+
+	page.SetContentPath(page.Href)
+
+	if err := b.registerSource(&page, sourcePath); err != nil {
 		return err
 	}
 
-	// A page like /blog/coffee/making-espresso.md will have /blog/coffee as
-	// route and making-espresso as ID.
-	page.Route = filepath.ToSlash(filepath.Dir(file))
-	page.ID = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
-	page.Href = filepath.ToSlash(filepath.Join(page.Route, page.ID))
+	if err := b.applyPermalink(&page); err != nil {
+		return err
+	}
+
+	// All URLs of a language start with the language's Href, like /de.
+	page.Href = path.Join(l.Href, page.Href)
+
+	if isBundle {
+		bundle.applyResources(contentDir, &page)
+	}
 
 	if err := b.setPageType(&page); err != nil {
 		return err
 	}
 
-	if err := b.Builder.RegisterPage(page); err != nil {
+	if err := l.taxonomies.ProcessPage(&page); err != nil {
+		return err
+	}
+
+	if err := l.builder.RegisterPage(page); err != nil {
 		return err
 	}
 
@@ -310,8 +661,101 @@ func (b *Build) processFile(contentDir, file string) error {
 	return nil
 }
 
+// applyPermalink overrides the ID of a page with its slug and applies the
+// permalink pattern for the page's section. The page's route remains
+// unchanged, so that the page is still listed in its section.
+func (b *Build) applyPermalink(page *model.Page) error {
+	// Custom list pages are identified by their ID and always use the
+	// route of their directory.
+	if page.IsCustomListPage() {
+		return nil
+	}
+
+	filename := page.ID
+
+	if page.Slug != "" {
+		page.ID = page.Slug
+		page.Href = path.Join(page.Route, page.ID)
+	}
+
+	href, ok, err := b.permalinks.href(page, filename)
+	if err != nil {
+		return err
+	}
+	if ok {
+		page.Href = href
+	}
+
+	return nil
+}
+
+// isIncluded determines whether a page is part of the website. Drafts,
+// pages scheduled for the future and expired pages are excluded unless
+// they are explicitly included using the build options.
+func (b *Build) isIncluded(page *model.Page) bool {
+	switch {
+	case page.Draft && !b.Options.Drafts:
+		return false
+	case page.IsFuture(b.now) && !b.Options.Future:
+		return false
+	case page.IsExpired(b.now) && !b.Options.Expired:
+		return false
+	}
+	return true
+}
+
+// registerSource makes sure that no other content file results in the
+// same page, like about.html next to about.md.
+func (b *Build) registerSource(page *model.Page, sourcePath string) error {
+	b.sourcesMutex.Lock()
+	defer b.sourcesMutex.Unlock()
+
+	key := page.Language + ":" + page.ContentPath()
+
+	if other, exists := b.sources[key]; exists {
+		files := []string{other, sourcePath}
+		sort.Strings(files)
+		return fmt.Errorf("files %s and %s result in the same page %s", files[0], files[1], page.ContentPath())
+	}
+
+	b.sources[key] = sourcePath
+
+	return nil
+}
+
+// loadPage returns the page for the given content file along with the
+// hash of the file. Files that haven't changed since the last build are
+// loaded from the build cache instead of being parsed again. In partial
+// builds, files that haven't changed aren't even read.
+func (b *Build) loadPage(file, path string) (model.Page, string, error) {
+	if b.changed != nil && !b.changed[path] {
+		if page, hash, cached := b.Cache.Source(file); cached {
+			return page, hash, nil
+		}
+	}
+
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return model.Page{}, "", err
+	}
+
+	hash := cache.Hash(src)
+
+	if page, cached := b.Cache.Page(file, hash); cached {
+		return page, hash, nil
+	}
+
+	page, err := b.Parsers[strings.ToLower(filepath.Ext(file))].ParsePage(src)
+	if err != nil {
+		return model.Page{}, "", err
+	}
+
+	return page, hash, nil
+}
+
 // setPageType sets the Type field of a page if a page type has been
-// provided by the user.
+// provided by the user. The page's front matter has to match the fields
+// declared by the type.
 func (b *Build) setPageType(page *model.Page) error {
 	providedType := page.ProvidedType()
 
@@ -325,6 +769,107 @@ func (b *Build) setPageType(page *model.Page) error {
 	}
 	page.Type = b.Types[providedType]
 
+	// Validate the page against the fields declared by its type and add
+	// the default values of missing fields.
+	params, err := parser.ApplySchema(page.Type, page.Params)
+	if err != nil {
+		return errors.Wrapf(err, "type %s", providedType)
+	}
+	page.Params = params
+
+	return nil
+}
+
+// taxonomies returns all taxonomies declared in the configuration. For
+// backwards compatibility, enabling the tags plugin declares the tags
+// taxonomy with its default settings.
+func taxonomies(cfg *config.Config) map[string]config.Taxonomy {
+	taxonomies := make(map[string]config.Taxonomy, len(cfg.Taxonomies)+1)
+
+	for name, taxonomyCfg := range cfg.Taxonomies {
+		taxonomies[name] = taxonomyCfg
+	}
+
+	for _, key := range cfg.Plugins {
+		if _, exists := taxonomies[taxonomy.Tags]; key == taxonomy.Tags && !exists {
+			taxonomies[taxonomy.Tags] = config.Taxonomy{}
+		}
+	}
+
+	// Terms are sorted like all other list pages unless configured otherwise.
+	for name, taxonomyCfg := range taxonomies {
+		if taxonomyCfg.SortBy == "" && taxonomyCfg.SortOrder == "" {
+			taxonomyCfg.SortBy = cfg.Sorting.SortBy
+			taxonomyCfg.SortOrder = cfg.Sorting.SortOrder
+			taxonomies[name] = taxonomyCfg
+		}
+	}
+
+	return taxonomies
+}
+
+// defaultParsers returns the parsers for all content file extensions
+// supported by verless.
+func defaultParsers() map[string]Parser {
+	markdown := parser.NewMarkdown()
+
+	return map[string]Parser{
+		".md":       markdown,
+		".markdown": markdown,
+		".html":     parser.NewHTML(),
+		".txt":      parser.NewText(),
+	}
+}
+
+// normalizeExtension converts an extension like MD to the form .md.
+func normalizeExtension(extension string) string {
+	extension = strings.ToLower(extension)
+	if !strings.HasPrefix(extension, ".") {
+		extension = "." + extension
+	}
+	return extension
+}
+
+// loadCache loads the build cache for the project in path. The cache is
+// only valid for the given output directory and configurations, meaning
+// that building into another directory or any change to the project or
+// theme configuration invalidates the entire cache.
+func loadCache(targetFs afero.Fs, path, outputDir string, cfg *config.Config, themeCfg *theme.Config, disable bool) (*cache.Cache, error) {
+	// The outputs recorded in the cache are pruned if they aren't written
+	// again, so they must never refer to another output directory.
+	absOutputDir, err := filepath.Abs(outputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := cache.HashValues(absOutputDir, cfg, themeCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if disable {
+		return cache.New(targetFs, path, key), nil
+	}
+
+	return cache.Load(targetFs, path, key), nil
+}
+
+// addConfigFiles adds the project configuration files to the dependency
+// graph. A changed configuration file affects all outputs.
+func addConfigFiles(depGraph *graph.Graph, path string) error {
+	files, err := filepath.Glob(filepath.Join(path, config.Filename+".*"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return err
+		}
+		depGraph.Add(graph.Config, abs, graph.All)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,125 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/verless/verless/model"
+	"github.com/verless/verless/parser"
+	"github.com/verless/verless/test"
+)
+
+// TestBuild_isIncluded checks if drafts, future pages and expired pages
+// are only included when the corresponding build option is set.
+func TestBuild_isIncluded(t *testing.T) {
+	now := time.Date(2021, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		page     model.Page
+		options  BuildOptions
+		included bool
+	}{
+		"regular page": {
+			page:     model.Page{Date: now.AddDate(0, 0, -1)},
+			included: true,
+		},
+		"draft": {
+			page: model.Page{Draft: true},
+		},
+		"draft with --drafts": {
+			page:     model.Page{Draft: true},
+			options:  BuildOptions{Drafts: true},
+			included: true,
+		},
+		"future publish date": {
+			page: model.Page{PublishDate: now.AddDate(0, 0, 1)},
+		},
+		"future date without publish date": {
+			page: model.Page{Date: now.AddDate(0, 0, 1)},
+		},
+		"future publish date with --future": {
+			page:     model.Page{PublishDate: now.AddDate(0, 0, 1)},
+			options:  BuildOptions{Future: true},
+			included: true,
+		},
+		"past expiry date": {
+			page: model.Page{ExpiryDate: now.AddDate(0, 0, -1)},
+		},
+		"future expiry date": {
+			page:     model.Page{ExpiryDate: now.AddDate(0, 0, 1)},
+			included: true,
+		},
+		"past expiry date with --expired": {
+			page:     model.Page{ExpiryDate: now.AddDate(0, 0, -1)},
+			options:  BuildOptions{Expired: true},
+			included: true,
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		b := Build{
+			Options: testCase.options,
+			now:     now,
+		}
+
+		test.Equals(t, testCase.included, b.isIncluded(&testCase.page))
+	}
+}
+
+// TestBuild_RegisterParser checks if content files are only processed if
+// there is a parser registered for their extension.
+func TestBuild_RegisterParser(t *testing.T) {
+	b := Build{
+		Parsers: defaultParsers(),
+	}
+
+	b.RegisterParser("ADOC", parser.NewText())
+
+	tests := map[string]bool{
+		"blog/espresso.md":       true,
+		"blog/espresso.markdown": true,
+		"blog/espresso.html":     true,
+		"blog/espresso.txt":      true,
+		"blog/espresso.adoc":     true,
+		"blog/espresso.MD":       true,
+		"blog/espresso.jpg":      false,
+	}
+
+	for file, expected := range tests {
+		t.Log(file)
+		test.Equals(t, expected, b.hasParser(file))
+	}
+}
+
+// TestBuild_registerSource checks if an error is returned when two
+// content files result in the same page.
+func TestBuild_registerSource(t *testing.T) {
+	b := Build{
+		sources: make(map[string]string),
+	}
+
+	tests := []struct {
+		file        string
+		language    string
+		contentPath string
+		expectError bool
+	}{
+		{file: "/content/about.md", contentPath: "/about"},
+		{file: "/content/about.de.md", language: "de", contentPath: "/about"},
+		{file: "/content/about.html", contentPath: "/about", expectError: true},
+		{file: "/content/blog/index.md", contentPath: "/blog/index"},
+		{file: "/content/blog/index.txt", contentPath: "/blog/index", expectError: true},
+	}
+
+	for _, testCase := range tests {
+		t.Log(testCase.file)
+
+		page := model.Page{Language: testCase.language}
+		page.SetContentPath(testCase.contentPath)
+
+		err := b.registerSource(&page, testCase.file)
+		test.Equals(t, testCase.expectError, err != nil)
+	}
+}
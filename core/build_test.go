@@ -2,6 +2,7 @@ package core_test
 
 import (
 	"log"
+	"path/filepath"
 	"testing"
 
 	"github.com/spf13/afero"
@@ -33,3 +34,54 @@ func TestRunFullBuild(t *testing.T) {
 		log.Println(err)
 	}
 }
+
+// TestRunIncrementalBuild tests if a second build of an unchanged project
+// reuses all pages from the build cache.
+func TestRunIncrementalBuild(t *testing.T) {
+	o := core.BuildOptions{
+		OutputDir: outTestPath,
+		Overwrite: true,
+	}
+
+	memMapFs := afero.NewMemMapFs()
+
+	build, err := core.NewBuild(memMapFs, "../example", o)
+	test.Ok(t, err)
+	test.Ok(t, build.Run())
+
+	first := build.Stats()
+	test.Assert(t, first.Rebuilt > 0, "the first build has to render all pages")
+
+	build, err = core.NewBuild(memMapFs, "../example", o)
+	test.Ok(t, err)
+	test.Ok(t, build.Run())
+
+	second := build.Stats()
+	test.Equals(t, 0, second.Rebuilt)
+	test.Equals(t, first.Rebuilt, second.Reused)
+}
+
+// TestRunBuild_outputDirs checks if building into another output directory
+// neither reuses nor removes the outputs of the previous build.
+func TestRunBuild_outputDirs(t *testing.T) {
+	memMapFs := afero.NewMemMapFs()
+
+	outputDirs := []string{"../output-dir-a", "../output-dir-b"}
+
+	for _, outputDir := range outputDirs {
+		build, err := core.NewBuild(memMapFs, "../example", core.BuildOptions{
+			OutputDir: outputDir,
+			Overwrite: true,
+		})
+		test.Ok(t, err)
+		test.Ok(t, build.Run())
+
+		test.Equals(t, 0, build.Stats().Reused)
+	}
+
+	for _, outputDir := range outputDirs {
+		exists, err := afero.Exists(memMapFs, filepath.Join(outputDir, "index.html"))
+		test.Ok(t, err)
+		test.Assert(t, exists, "%s must still contain the outputs of its build", outputDir)
+	}
+}
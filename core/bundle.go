@@ -0,0 +1,148 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/verless/verless/model"
+)
+
+// bundle represents a leaf bundle: A content directory that only contains
+// an index file like index.md along with resources like images. If the
+// index file sets Bundle in its front matter, the entire directory is
+// rendered as a single page.
+type bundle struct {
+	// dir is the directory path relative to the content directory.
+	dir string
+	// resources contains the file names of all resources.
+	resources []string
+}
+
+// findBundles finds all leaf bundles inside the content directory. The
+// returned map is keyed by the path of the bundle's index file relative
+// to the content directory, in the same form as streamed by StreamFiles.
+//
+// A directory is a leaf bundle if it contains an index file that can be
+// parsed, no other content files and no sub-directories. Since the front
+// matter isn't known yet, the index file still has to opt in using the
+// Bundle key, otherwise it is processed as list page. On multilingual
+// websites, a bundle contains an index file for each language, like
+// index.md and index.de.md. The content directory itself is never a
+// bundle.
+//
+// indexLanguage determines whether a file is an index file and returns
+// the code of the file's language.
+func findBundles(contentDir string, hasParser func(file string) bool, indexLanguage func(file string) (string, bool)) (map[string]*bundle, error) {
+	bundles := make(map[string]*bundle)
+
+	err := filepath.Walk(contentDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == contentDir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !info.IsDir() || path == contentDir {
+			return nil
+		}
+
+		indexes, b, err := readBundle(path, hasParser, indexLanguage)
+		if err != nil || b == nil {
+			return err
+		}
+
+		b.dir = strings.TrimPrefix(path, contentDir)
+		for _, index := range indexes {
+			bundles[filepath.Join(b.dir, index)] = b
+		}
+
+		return filepath.SkipDir
+	})
+
+	return bundles, err
+}
+
+// readBundle checks whether the given directory is a leaf bundle. If it
+// is, readBundle returns the names of the index files along with a bundle
+// whose dir is still empty. Otherwise, the bundle is nil.
+func readBundle(dir string, hasParser func(file string) bool, indexLanguage func(file string) (string, bool)) ([]string, *bundle, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		indexes   []string
+		languages = make(map[string]bool)
+		resources []string
+	)
+
+	for _, file := range files {
+		name := file.Name()
+
+		if file.IsDir() {
+			return nil, nil, nil
+		}
+		if strings.HasPrefix(name, "_") {
+			continue
+		}
+		if !hasParser(name) {
+			resources = append(resources, name)
+			continue
+		}
+
+		// Directories with multiple content files in the same language are
+		// sections.
+		language, isIndex := indexLanguage(name)
+		if !isIndex || languages[language] {
+			return nil, nil, nil
+		}
+
+		indexes = append(indexes, name)
+		languages[language] = true
+	}
+
+	if len(indexes) == 0 {
+		return nil, nil, nil
+	}
+
+	sort.Strings(resources)
+
+	return indexes, &bundle{resources: resources}, nil
+}
+
+// isIndexFile determines whether a file is an index file like index.md.
+func isIndexFile(name string) bool {
+	return strings.TrimSuffix(name, filepath.Ext(name)) == "index"
+}
+
+// apply turns a page parsed from the given index file into the page
+// representing the entire bundle. The path of the index file is relative
+// to the page's language. A bundle stored in /blog/espresso will have
+// /blog as route and espresso as ID.
+func (b *bundle) apply(page *model.Page, index string) {
+	dir := filepath.ToSlash(filepath.Dir(index))
+
+	page.Route = path.Dir(dir)
+	page.ID = path.Base(dir)
+	page.Href = dir
+}
+
+// applyResources assigns the resources of the bundle to the page. The
+// resources are located right next to the page, so the page's Href has
+// to be final when calling applyResources.
+func (b *bundle) applyResources(contentDir string, page *model.Page) {
+	page.Resources = make([]model.Resource, len(b.resources))
+
+	for i, name := range b.resources {
+		page.Resources[i] = model.NewResource(
+			name,
+			path.Join(page.Href, name),
+			filepath.Join(contentDir, b.dir, name),
+		)
+	}
+}
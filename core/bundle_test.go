@@ -0,0 +1,135 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/verless/verless/config"
+	"github.com/verless/verless/model"
+	"github.com/verless/verless/test"
+)
+
+// TestFindBundles checks if only directories with a single index file
+// per language and no sub-directories are treated as leaf bundles.
+func TestFindBundles(t *testing.T) {
+	contentDir, err := ioutil.TempDir("", "verless-bundles")
+	test.Ok(t, err)
+	defer func() { _ = os.RemoveAll(contentDir) }()
+
+	files := []string{
+		"index.md",
+		"about.md",
+		"blog/index.md",
+		"blog/coffee.md",
+		"blog/espresso/index.md",
+		"blog/espresso/crema.jpg",
+		"blog/espresso/beans.png",
+		"blog/cappuccino/index.md",
+		"blog/cappuccino/milk.md",
+		"recipes/index.md",
+		"recipes/soup/index.md",
+		"recipes/salad/index.md",
+		"recipes/salad/index.html",
+		"blog/latte/index.md",
+		"blog/latte/index.de.md",
+		"blog/latte/foam.jpg",
+	}
+
+	for _, file := range files {
+		path := filepath.Join(contentDir, file)
+		test.Ok(t, os.MkdirAll(filepath.Dir(path), 0755))
+		test.Ok(t, ioutil.WriteFile(path, []byte("---\nTitle: Test\n---"), 0644))
+	}
+
+	languages, err := newLanguages(&config.Config{
+		DefaultLanguage: "en",
+		Languages:       map[string]config.Language{"en": {}, "de": {}},
+	})
+	test.Ok(t, err)
+
+	b := Build{Parsers: defaultParsers(), languages: languages}
+
+	bundles, err := findBundles(contentDir, b.hasParser, b.indexLanguage)
+	test.Ok(t, err)
+
+	espresso := filepath.FromSlash("/blog/espresso/index.md")
+	soup := filepath.FromSlash("/recipes/soup/index.md")
+	latte := filepath.FromSlash("/blog/latte/index.md")
+	latteDE := filepath.FromSlash("/blog/latte/index.de.md")
+
+	test.Equals(t, 4, len(bundles))
+	test.Equals(t, []string{"beans.png", "crema.jpg"}, bundles[espresso].resources)
+	test.Equals(t, 0, len(bundles[soup].resources))
+	test.Assert(t, bundles[latte] == bundles[latteDE], "translated index files should belong to the same bundle")
+
+	var page model.Page
+	bundles[espresso].apply(&page, espresso)
+	bundles[espresso].applyResources(contentDir, &page)
+
+	test.Equals(t, "/blog", page.Route)
+	test.Equals(t, "espresso", page.ID)
+	test.Equals(t, "/blog/espresso", page.Href)
+	test.Equals(t, "/blog/espresso/crema.jpg", page.Resources[1].Href)
+	test.Equals(t, filepath.Join(contentDir, "blog", "espresso", "crema.jpg"), page.Resources[1].SourcePath())
+
+	// Resources are located next to the page, even if its Href changes.
+	page.Href = "/blog/2020/espresso"
+	bundles[espresso].applyResources(contentDir, &page)
+
+	test.Equals(t, "/blog/2020/espresso/crema.jpg", page.Resources[1].Href)
+}
+
+// TestBuild_bundles checks if only directories whose index file sets
+// Bundle are rendered as page bundles, while an index file stored next to
+// images without Bundle remains a list page.
+func TestBuild_bundles(t *testing.T) {
+	path, err := ioutil.TempDir("", "verless-bundles")
+	test.Ok(t, err)
+	defer func() { _ = os.RemoveAll(path) }()
+
+	files := map[string]string{
+		"verless.yml":                             "version: 1\ntheme: default\n",
+		"themes/default/templates/page.html":      "page {{.Page.Title}}",
+		"themes/default/templates/list-page.html": "list {{.Page.Title}}",
+		"content/gallery/index.md":                "---\nTitle: Gallery\n---",
+		"content/gallery/crema.jpg":               "",
+		"content/espresso/index.md":               "---\nTitle: Espresso\nBundle: true\n---",
+		"content/espresso/crema.jpg":              "",
+	}
+
+	for file, content := range files {
+		file = filepath.Join(path, file)
+		test.Ok(t, os.MkdirAll(filepath.Dir(file), 0755))
+		test.Ok(t, ioutil.WriteFile(file, []byte(content), 0644))
+	}
+
+	memMapFs := afero.NewMemMapFs()
+	outputDir := filepath.Join(path, "target")
+
+	build, err := NewBuild(memMapFs, path, BuildOptions{OutputDir: outputDir, Overwrite: true})
+	test.Ok(t, err)
+	test.Ok(t, build.Run())
+
+	tests := map[string]struct {
+		content   string
+		resources bool
+	}{
+		"gallery":  {content: "list Gallery"},
+		"espresso": {content: "page Espresso", resources: true},
+	}
+
+	for dir, testCase := range tests {
+		t.Log(dir)
+
+		content, err := afero.ReadFile(memMapFs, filepath.Join(outputDir, dir, "index.html"))
+		test.Ok(t, err)
+		test.Equals(t, testCase.content, string(content))
+
+		exists, err := afero.Exists(memMapFs, filepath.Join(outputDir, dir, "crema.jpg"))
+		test.Ok(t, err)
+		test.Equals(t, testCase.resources, exists)
+	}
+}
@@ -62,5 +62,6 @@ build:
     # - Another command there
 `)
 
-	defaultGitignore = []byte(`generated/`)
+	defaultGitignore = []byte(`generated/
+.verless/`)
 )
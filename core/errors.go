@@ -0,0 +1,65 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/verless/verless/parser"
+)
+
+// Errors is a collection of errors that occurred during a build, for
+// example while processing multiple content files in parallel.
+type Errors []error
+
+// Error implements the error interface. Each error is printed on its own
+// line, so that all errors can be reported at once.
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = "  " + err.Error()
+	}
+	return fmt.Sprintf("errors while processing files:\n%s", strings.Join(messages, "\n"))
+}
+
+// FileError represents an error that arises when processing a content
+// file.
+type FileError struct {
+	// File is the absolute path of the content file.
+	File string
+	// Line is the line inside the file that caused the error. It is 0
+	// if the line is unknown.
+	Line int
+	// Err is the actual error.
+	Err error
+}
+
+// newFileError creates a new FileError for the given file and tries to
+// determine the line that caused the error.
+func newFileError(file string, err error) *FileError {
+	fileErr := FileError{
+		File: file,
+		Err:  err,
+	}
+
+	var syntaxErr *parser.SyntaxError
+
+	if errors.As(err, &syntaxErr) {
+		fileErr.Line = syntaxErr.Line
+	}
+
+	return &fileErr
+}
+
+// Error implements the error interface.
+func (e *FileError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.File, e.Err)
+	}
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Err)
+}
+
+// Unwrap returns the actual error.
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
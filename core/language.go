@@ -0,0 +1,244 @@
+package core
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/verless/verless/builder"
+	"github.com/verless/verless/config"
+	"github.com/verless/verless/model"
+	"github.com/verless/verless/taxonomy"
+	"github.com/verless/verless/tree"
+)
+
+// language represents a language of the website along with the builder
+// and the taxonomies maintaining the language's site. Websites without
+// any declared languages consist of a single language without a code.
+type language struct {
+	model.Language
+	// contentDir is the directory inside the content directory that only
+	// contains pages in this language, like /de. It may be empty.
+	contentDir string
+	builder    Builder
+	taxonomies *taxonomy.Taxonomies
+}
+
+// newLanguages creates all languages declared in the configuration. The
+// default language comes first, followed by all other languages ordered
+// by their weight and code.
+func newLanguages(cfg *config.Config) ([]*language, error) {
+	if len(cfg.Languages) == 0 {
+		return []*language{newLanguage(cfg, model.Language{}, "")}, nil
+	}
+
+	if _, exists := cfg.Languages[cfg.DefaultLanguage]; !exists {
+		return nil, fmt.Errorf("default language %s has not been declared in languages", cfg.DefaultLanguage)
+	}
+
+	codes := make([]string, 0, len(cfg.Languages))
+	for code := range cfg.Languages {
+		codes = append(codes, code)
+	}
+
+	sort.Slice(codes, func(i, j int) bool {
+		a, b := cfg.Languages[codes[i]], cfg.Languages[codes[j]]
+		switch {
+		case codes[i] == cfg.DefaultLanguage || codes[j] == cfg.DefaultLanguage:
+			return codes[i] == cfg.DefaultLanguage
+		case a.Weight != b.Weight:
+			return a.Weight < b.Weight
+		}
+		return codes[i] < codes[j]
+	})
+
+	languages := make([]*language, 0, len(codes))
+
+	for _, code := range codes {
+		languageCfg := cfg.Languages[code]
+
+		l := model.Language{
+			Code: code,
+			Name: languageCfg.Name,
+			Href: "/" + code,
+		}
+
+		if l.Name == "" {
+			l.Name = code
+		}
+
+		// The pages of the default language are served from the root.
+		if code == cfg.DefaultLanguage {
+			l.Href = tree.RootPath
+		}
+
+		var contentDir string
+		if languageCfg.ContentDir != "" {
+			contentDir = path.Clean("/" + filepath.ToSlash(languageCfg.ContentDir))
+		}
+
+		languages = append(languages, newLanguage(cfg, l, contentDir))
+	}
+
+	return languages, nil
+}
+
+// newLanguage creates a language with its own builder and taxonomies. The
+// builder uses the website data overridden by the language.
+func newLanguage(cfg *config.Config, l model.Language, contentDir string) *language {
+	languageCfg := cfg.ForLanguage(l.Code)
+
+	return &language{
+		Language:   l,
+		contentDir: contentDir,
+		builder:    builder.NewLanguage(&languageCfg, l),
+		taxonomies: taxonomy.NewLanguage(taxonomies(cfg), l),
+	}
+}
+
+// languageOf determines the language of the given content file and returns
+// it along with the path of the file relative to the language.
+//
+// A file belongs to a language if it is stored in the language's content
+// directory, like /de/about.md, or if its filename has the language code
+// as suffix, like /about.de.md. In both cases, the relative path will be
+// /about.md. All other files belong to the default language.
+func (b *Build) languageOf(file string) (*language, string) {
+	slashed := filepath.ToSlash(file)
+
+	for _, l := range b.languages {
+		if l.contentDir != "" && strings.HasPrefix(slashed, l.contentDir+"/") {
+			return l, filepath.FromSlash(strings.TrimPrefix(slashed, l.contentDir))
+		}
+	}
+
+	ext := filepath.Ext(file)
+	name := strings.TrimSuffix(file, ext)
+	suffix := filepath.Ext(name)
+
+	for _, l := range b.languages {
+		if l.Code != "" && suffix == "."+l.Code {
+			return l, strings.TrimSuffix(name, suffix) + ext
+		}
+	}
+
+	return b.languages[0], file
+}
+
+// indexLanguage determines whether a file is an index file like index.md
+// or a translated index file like index.de.md. It returns the code of the
+// file's language.
+func (b *Build) indexLanguage(file string) (string, bool) {
+	l, file := b.languageOf(file)
+	return l.Code, isIndexFile(filepath.Base(file))
+}
+
+// siteLanguages returns the languages as they are available in templates.
+// For websites without any declared languages, siteLanguages returns nil.
+func (b *Build) siteLanguages() []model.Language {
+	if len(b.languages) == 1 && b.languages[0].Code == "" {
+		return nil
+	}
+
+	languages := make([]model.Language, len(b.languages))
+	for i, l := range b.languages {
+		languages[i] = l.Language
+	}
+
+	return languages
+}
+
+// translatedStrings returns the translated strings of the language, given
+// the strings of all languages keyed by their code. Strings that haven't
+// been translated are taken from the default language.
+func (l *language) translatedStrings(i18n map[string]interface{}, defaultLanguage string) map[string]interface{} {
+	translated := make(map[string]interface{})
+
+	for _, code := range []string{defaultLanguage, l.Code} {
+		if values, ok := i18n[code].(map[string]interface{}); ok {
+			for key, val := range values {
+				translated[key] = val
+			}
+		}
+	}
+
+	return translated
+}
+
+// linkTranslations links each page to the same page in all other
+// languages. Pages are the same if they have the same path inside the
+// content directory of their language, like /about for both /about.md
+// and /about.de.md.
+//
+// List pages and term pages may reference copies of a page instead of
+// the page itself, so the translations are assigned to every page that
+// is reachable from the site's route tree.
+func linkTranslations(sites []model.Site) {
+	if len(sites) < 2 {
+		return
+	}
+
+	pages := make(map[string][]*model.Page)
+
+	walkPages(sites, func(page *model.Page, canonical bool) {
+		if canonical {
+			pages[page.ContentPath()] = append(pages[page.ContentPath()], page)
+		}
+	})
+
+	walkPages(sites, func(page *model.Page, _ bool) {
+		page.Translations = nil
+		for _, translation := range pages[page.ContentPath()] {
+			if translation.Language != page.Language {
+				page.Translations = append(page.Translations, translation)
+			}
+		}
+	})
+}
+
+// walkPages calls fn for all pages reachable from the route trees of the
+// given sites. Pages and custom list pages stored in a node are canonical,
+// pages referenced by list pages are not.
+func walkPages(sites []model.Site, fn func(page *model.Page, canonical bool)) {
+	for i := range sites {
+		_ = tree.Walk(sites[i].Root, func(_ string, node tree.Node) error {
+			n := node.(*model.Node)
+
+			if n.ListPage.IsCustomListPage() {
+				fn(&n.ListPage.Page, true)
+			}
+			for j := range n.Pages {
+				fn(&n.Pages[j], true)
+			}
+			for _, p := range n.ListPage.Pages {
+				fn(p, false)
+			}
+
+			return nil
+		}, -1)
+	}
+}
+
+// mergeRedirects merges the redirects of all sites. An alias must not be
+// used in multiple languages.
+func mergeRedirects(sites []model.Site) ([]model.Redirect, error) {
+	redirects := make([]model.Redirect, 0)
+
+	for _, site := range sites {
+		redirects = append(redirects, site.Redirects...)
+	}
+
+	sort.Slice(redirects, func(i, j int) bool {
+		return redirects[i].From < redirects[j].From
+	})
+
+	for i := 1; i < len(redirects); i++ {
+		if redirects[i-1].From == redirects[i].From {
+			return nil, fmt.Errorf("alias %s is used by pages %s and %s", redirects[i].From, redirects[i-1].To, redirects[i].To)
+		}
+	}
+
+	return redirects, nil
+}
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/verless/verless/config"
+	"github.com/verless/verless/model"
+	"github.com/verless/verless/test"
+)
+
+// testLanguages declares the languages used for testing.
+var testLanguages = config.Config{
+	DefaultLanguage: "en",
+	Languages: map[string]config.Language{
+		"fr": {Weight: 2},
+		"en": {Name: "English", Weight: 3},
+		"de": {Name: "Deutsch", ContentDir: "deutsch", Weight: 1},
+	},
+}
+
+// TestNewLanguages checks if the default language comes first and if all
+// other languages are ordered by their weight.
+func TestNewLanguages(t *testing.T) {
+	languages, err := newLanguages(&testLanguages)
+	test.Ok(t, err)
+
+	expected := []model.Language{
+		{Code: "en", Name: "English", Href: "/"},
+		{Code: "de", Name: "Deutsch", Href: "/de"},
+		{Code: "fr", Name: "fr", Href: "/fr"},
+	}
+
+	test.Equals(t, len(expected), len(languages))
+	for i, l := range languages {
+		test.Equals(t, expected[i], l.Language)
+	}
+
+	cfg := testLanguages
+	cfg.DefaultLanguage = "es"
+
+	_, err = newLanguages(&cfg)
+	test.Assert(t, err != nil, "an undeclared default language should return an error")
+}
+
+// TestBuild_languageOf checks if content files are assigned to languages
+// by their content directory and their filename suffix.
+func TestBuild_languageOf(t *testing.T) {
+	languages, err := newLanguages(&testLanguages)
+	test.Ok(t, err)
+
+	b := Build{languages: languages}
+
+	tests := map[string]struct {
+		file             string
+		expectedLanguage string
+		expectedFile     string
+	}{
+		"default language": {
+			file:             "/blog/espresso.md",
+			expectedLanguage: "en",
+			expectedFile:     "/blog/espresso.md",
+		},
+		"filename suffix": {
+			file:             "/blog/espresso.fr.md",
+			expectedLanguage: "fr",
+			expectedFile:     "/blog/espresso.md",
+		},
+		"content directory": {
+			file:             "/deutsch/blog/espresso.md",
+			expectedLanguage: "de",
+			expectedFile:     "/blog/espresso.md",
+		},
+		"unknown suffix": {
+			file:             "/blog/espresso.it.md",
+			expectedLanguage: "en",
+			expectedFile:     "/blog/espresso.it.md",
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		l, file := b.languageOf(filepath.FromSlash(testCase.file))
+		test.Equals(t, testCase.expectedLanguage, l.Code)
+		test.Equals(t, filepath.FromSlash(testCase.expectedFile), file)
+	}
+}
+
+// TestLinkTranslations checks if pages with the same content path are
+// linked across all sites.
+func TestLinkTranslations(t *testing.T) {
+	sites := make([]model.Site, 2)
+
+	for i, code := range []string{"en", "de"} {
+		sites[i] = model.NewSite()
+
+		about := model.Page{ID: "about", Route: "/", Href: "/" + code + "/about", Language: code}
+		about.SetContentPath("/about")
+
+		imprint := model.Page{ID: "imprint", Route: "/", Href: "/" + code + "/imprint-" + code, Language: code}
+		imprint.SetContentPath("/imprint-" + code)
+
+		sites[i].Root.Pages = []model.Page{about, imprint}
+
+		// List pages may reference a copy of the page.
+		listed := about
+		sites[i].Root.ListPage.Pages = []*model.Page{&listed}
+	}
+
+	linkTranslations(sites)
+
+	en, de := sites[0].Root.Pages, sites[1].Root.Pages
+
+	test.Equals(t, 1, len(en[0].Translations))
+	test.Equals(t, "/de/about", en[0].Translations[0].Href)
+	test.Equals(t, "/en/about", de[0].Translations[0].Href)
+	test.Equals(t, 0, len(en[1].Translations))
+
+	listed := sites[0].Root.ListPage.Pages[0]
+	test.Equals(t, 1, len(listed.Translations))
+	test.Equals(t, "/de/about", listed.Translations[0].Href)
+}
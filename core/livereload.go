@@ -0,0 +1,208 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	// liveReloadPath is the URL path of the live reload event stream.
+	liveReloadPath string = "/_verless/livereload"
+
+	// reloadEvent tells the browser to reload the entire page.
+	reloadEvent string = "reload"
+	// cssEvent tells the browser to reload all stylesheets.
+	cssEvent string = "css"
+)
+
+var (
+	// liveReloadScript is injected into all served HTML pages. It listens
+	// for reload events and either reloads the page or the stylesheets.
+	liveReloadScript = []byte(`<script>
+(function () {
+    var source = new EventSource("` + liveReloadPath + `");
+    source.addEventListener("` + reloadEvent + `", function () {
+        window.location.reload();
+    });
+    source.addEventListener("` + cssEvent + `", function () {
+        var links = document.querySelectorAll('link[rel="stylesheet"]');
+        for (var i = 0; i < links.length; i++) {
+            var url = new URL(links[i].href);
+            url.searchParams.set("verless-reload", Date.now());
+            links[i].href = url.toString();
+        }
+    });
+})();
+</script>
+`)
+)
+
+// liveReload is an HTTP handler that sends reload events to all connected
+// browsers using Server-Sent Events. It is safe for concurrent usage.
+type liveReload struct {
+	clients map[chan string]struct{}
+	mutex   sync.Mutex
+	overlay overlay
+}
+
+// newLiveReload creates a new liveReload instance without any clients.
+func newLiveReload() *liveReload {
+	l := liveReload{
+		clients: make(map[chan string]struct{}),
+	}
+	return &l
+}
+
+// ServeHTTP streams all reload events to the client until the client
+// disconnects or the live reload is closed.
+func (l *liveReload) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher.Flush()
+
+	events := make(chan string, 1)
+
+	l.mutex.Lock()
+	l.clients[events] = struct{}{}
+	l.mutex.Unlock()
+
+	defer func() {
+		l.mutex.Lock()
+		delete(l.clients, events)
+		l.mutex.Unlock()
+	}()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, event)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// notify sends the given event to all connected clients. Clients that
+// haven't received the previous event yet will skip the new one.
+func (l *liveReload) notify(event string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for client := range l.clients {
+		select {
+		case client <- event:
+		default:
+		}
+	}
+}
+
+// close disconnects all clients. This is required for shutting down the
+// server because it would wait for the open event streams otherwise.
+func (l *liveReload) close() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for client := range l.clients {
+		close(client)
+		delete(l.clients, client)
+	}
+}
+
+// eventFor returns the reload event for a changed file. Changed styles
+// can be swapped without reloading the entire page.
+func eventFor(file string) string {
+	if filepath.Ext(file) == ".css" {
+		return cssEvent
+	}
+	return reloadEvent
+}
+
+// withLiveReload returns a handler that serves the live reload event
+// stream and injects the live reload script into all HTML pages. If the
+// last build has failed, the error overlay is injected as well. All other
+// requests are passed to the next handler.
+func withLiveReload(fs afero.Fs, root string, reload *liveReload, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == liveReloadPath {
+			reload.ServeHTTP(w, r)
+			return
+		}
+
+		file := htmlFile(fs, root, r.URL.Path)
+		if file == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		content, err := afero.ReadFile(fs, file)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(injectScript(content, append(reload.overlay.html(), liveReloadScript...)))
+	})
+}
+
+// htmlFile returns the path of the HTML file that will be served for
+// the given URL path. If no HTML file will be served, it returns an
+// empty string.
+func htmlFile(fs afero.Fs, root, urlPath string) string {
+	isDir := strings.HasSuffix(urlPath, "/")
+	file := filepath.Join(root, filepath.FromSlash(path.Clean("/"+urlPath)))
+
+	info, err := fs.Stat(file)
+	if err != nil {
+		return ""
+	}
+
+	// Directories without a trailing slash are redirected by the file
+	// server, so only directories with a trailing slash are handled.
+	if info.IsDir() {
+		if !isDir {
+			return ""
+		}
+		file = filepath.Join(file, "index.html")
+	}
+
+	if filepath.Ext(file) != ".html" {
+		return ""
+	}
+
+	return file
+}
+
+// injectScript inserts a script right before the closing body tag of
+// an HTML document. If there is no closing body tag, the script will be
+// appended to the document.
+func injectScript(content, script []byte) []byte {
+	i := bytes.LastIndex(content, []byte("</body>"))
+	if i == -1 {
+		return append(content, script...)
+	}
+
+	injected := make([]byte, 0, len(content)+len(script))
+	injected = append(injected, content[:i]...)
+	injected = append(injected, script...)
+	injected = append(injected, content[i:]...)
+
+	return injected
+}
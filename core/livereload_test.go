@@ -0,0 +1,66 @@
+package core
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/verless/verless/test"
+)
+
+// TestWithLiveReload checks if the live reload script is injected into
+// HTML pages and if all other files are served as they are.
+func TestWithLiveReload(t *testing.T) {
+	memMapFs := afero.NewMemMapFs()
+
+	test.Ok(t, afero.WriteFile(memMapFs, "/target/index.html", []byte("<html><body></body></html>"), 0644))
+	test.Ok(t, afero.WriteFile(memMapFs, "/target/blog/index.html", []byte("<p>Blog</p>"), 0644))
+	test.Ok(t, afero.WriteFile(memMapFs, "/target/style.css", []byte("body {}"), 0644))
+
+	handler := withLiveReload(memMapFs, "/target", newLiveReload(),
+		http.FileServer(afero.NewHttpFs(memMapFs).Dir("/target")))
+
+	tests := map[string]struct {
+		path     string
+		injected bool
+		body     string
+	}{
+		"index page": {
+			path:     "/",
+			injected: true,
+			body:     "<html><body>",
+		},
+		"page without body tag": {
+			path:     "/blog/",
+			injected: true,
+			body:     "<p>Blog</p>",
+		},
+		"stylesheet": {
+			path: "/style.css",
+			body: "body {}",
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, testCase.path, nil))
+
+		body, err := ioutil.ReadAll(recorder.Body)
+		test.Ok(t, err)
+
+		test.Equals(t, http.StatusOK, recorder.Code)
+		test.Assert(t, strings.HasPrefix(string(body), testCase.body), "unexpected body %s", body)
+		test.Equals(t, testCase.injected, strings.Contains(string(body), liveReloadPath))
+	}
+}
+
+// TestEventFor checks if changed stylesheets only trigger a CSS reload.
+func TestEventFor(t *testing.T) {
+	test.Equals(t, cssEvent, eventFor("/project/static/css/style.css"))
+	test.Equals(t, reloadEvent, eventFor("/project/content/blog/espresso.md"))
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"bytes"
+	"html/template"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/verless/verless/writer"
+)
+
+var (
+	// overlayTemplate renders the build errors as an overlay on top of
+	// the served page. It can be dismissed by clicking the close button.
+	overlayTemplate = template.Must(template.New("overlay").Parse(`<div id="verless-overlay" style="position:fixed;top:0;left:0;right:0;bottom:0;z-index:2147483647;overflow:auto;padding:2rem;background:rgba(0,0,0,0.85);color:#e8e8e8;font:14px/1.5 monospace;">
+    <button onclick="document.getElementById('verless-overlay').remove()" style="float:right;background:none;border:0;color:#e8e8e8;font-size:1.5rem;cursor:pointer;">&times;</button>
+    <h2 style="margin:0 0 1rem;color:#ff5555;">Failed to build the project</h2>
+    {{- range . }}
+    <div style="margin-bottom:1rem;padding:1rem;border-left:4px solid #ff5555;background:#1e1e1e;">
+        {{- if .File }}<div style="color:#8be9fd;">{{ .File }}{{ if .Line }}:{{ .Line }}{{ end }}</div>{{ end }}
+        {{- if .Template }}<div style="color:#f1fa8c;">template: {{ .Template }}</div>{{ end }}
+        <pre style="margin:0.5rem 0 0;white-space:pre-wrap;">{{ .Message }}</pre>
+    </div>
+    {{- end }}
+</div>
+`))
+)
+
+// overlayError holds the details of a single build error.
+type overlayError struct {
+	File     string
+	Line     int
+	Template string
+	Message  string
+}
+
+// overlay keeps the error of the last build. As long as there is an
+// error, the overlay is rendered into all served HTML pages. It is safe
+// for concurrent usage.
+type overlay struct {
+	err   error
+	mutex sync.RWMutex
+}
+
+// set stores the error of the last build. Passing nil clears the error
+// after a successful build.
+func (o *overlay) set(err error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.err = err
+}
+
+// failed reports whether the last build has failed.
+func (o *overlay) failed() bool {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	return o.err != nil
+}
+
+// html renders the current error as an HTML overlay. If there is no
+// error, html returns nil.
+func (o *overlay) html() []byte {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	if o.err == nil {
+		return nil
+	}
+
+	var buf bytes.Buffer
+
+	if err := overlayTemplate.Execute(&buf, overlayErrors(o.err)); err != nil {
+		return nil
+	}
+
+	return buf.Bytes()
+}
+
+// overlayErrors flattens the given error and extracts the file, line and
+// template details of each error.
+func overlayErrors(err error) []overlayError {
+	var buildErrs Errors
+
+	if errors.As(err, &buildErrs) {
+		overlayErrs := make([]overlayError, 0, len(buildErrs))
+		for _, buildErr := range buildErrs {
+			overlayErrs = append(overlayErrs, overlayErrors(buildErr)...)
+		}
+		return overlayErrs
+	}
+
+	overlayErr := overlayError{
+		Message: err.Error(),
+	}
+
+	var (
+		fileErr     *FileError
+		templateErr *writer.TemplateError
+	)
+
+	switch {
+	case errors.As(err, &fileErr):
+		overlayErr.File = fileErr.File
+		overlayErr.Line = fileErr.Line
+		overlayErr.Message = fileErr.Err.Error()
+	case errors.As(err, &templateErr):
+		overlayErr.File = templateErr.Path
+		overlayErr.Line = templateErr.Line
+		overlayErr.Template = templateErr.Template
+		overlayErr.Message = templateErr.Err.Error()
+	}
+
+	return []overlayError{overlayErr}
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/verless/verless/parser"
+	"github.com/verless/verless/test"
+	"github.com/verless/verless/writer"
+)
+
+// TestOverlayErrors checks if the file, line and template details are
+// extracted from build errors.
+func TestOverlayErrors(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected []overlayError
+	}{
+		"plain error": {
+			err: errors.New("something went wrong"),
+			expected: []overlayError{
+				{Message: "something went wrong"},
+			},
+		},
+		"file errors": {
+			err: Errors{
+				newFileError("/project/content/a.md", &parser.SyntaxError{
+					Format: "yaml",
+					Line:   3,
+					Err:    errors.New("did not find expected key"),
+				}),
+				newFileError("/project/content/b.md", errors.New("invalid date")),
+			},
+			expected: []overlayError{
+				{File: "/project/content/a.md", Line: 3, Message: "invalid yaml front matter: did not find expected key"},
+				{File: "/project/content/b.md", Message: "invalid date"},
+			},
+		},
+		"template error": {
+			err: &writer.TemplateError{
+				Template: "page.html",
+				Path:     "/project/themes/default/templates/page.html",
+				Line:     12,
+				Output:   "/target/blog/index.html",
+				Err:      errors.New("function \"foo\" not defined"),
+			},
+			expected: []overlayError{
+				{
+					File:     "/project/themes/default/templates/page.html",
+					Line:     12,
+					Template: "page.html",
+					Message:  "function \"foo\" not defined",
+				},
+			},
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+		test.Equals(t, testCase.expected, overlayErrors(testCase.err))
+	}
+}
+
+// TestOverlay_html checks if the overlay is only rendered while there is
+// an error and if the error message is escaped.
+func TestOverlay_html(t *testing.T) {
+	var o overlay
+
+	test.Assert(t, o.html() == nil, "expected no overlay without an error")
+
+	o.set(errors.New("unexpected <script>"))
+	test.Assert(t, o.failed(), "expected the build to be failed")
+
+	html := string(o.html())
+	test.Assert(t, strings.Contains(html, "unexpected &lt;script&gt;"), "expected escaped message in %s", html)
+
+	o.set(nil)
+	test.Assert(t, o.html() == nil, "expected no overlay after a successful build")
+}
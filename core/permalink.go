@@ -0,0 +1,88 @@
+package core
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/verless/verless/model"
+	"github.com/verless/verless/taxonomy"
+)
+
+var (
+	// placeholderExpr matches placeholders like :year in a permalink
+	// pattern.
+	placeholderExpr = regexp.MustCompile(`:[a-z]+`)
+
+	// placeholders contains all supported permalink placeholders along
+	// with a function that returns the placeholder's value for a page.
+	placeholders = map[string]func(page *model.Page, filename string) string{
+		":year":     func(page *model.Page, _ string) string { return page.Date.Format("2006") },
+		":month":    func(page *model.Page, _ string) string { return page.Date.Format("01") },
+		":day":      func(page *model.Page, _ string) string { return page.Date.Format("02") },
+		":slug":     func(page *model.Page, _ string) string { return page.ID },
+		":filename": func(_ *model.Page, filename string) string { return filename },
+		":title":    func(page *model.Page, _ string) string { return taxonomy.Slug(page.Title) },
+		":section":  func(page *model.Page, _ string) string { return strings.Split(strings.Trim(page.Route, "/"), "/")[0] },
+	}
+)
+
+// permalinks maps content sections like /blog to their permalink
+// patterns like /blog/:year/:month/:slug.
+type permalinks map[string]string
+
+// newPermalinks creates the permalink patterns declared in the given
+// configuration. It returns an error if a pattern contains an unknown
+// placeholder.
+func newPermalinks(cfg map[string]string) (permalinks, error) {
+	p := make(permalinks, len(cfg))
+
+	for section, pattern := range cfg {
+		for _, placeholder := range placeholderExpr.FindAllString(pattern, -1) {
+			if _, ok := placeholders[placeholder]; !ok {
+				return nil, fmt.Errorf("permalink pattern %s: unknown placeholder %s", pattern, placeholder)
+			}
+		}
+		p[path.Clean("/"+section)] = pattern
+	}
+
+	return p, nil
+}
+
+// href returns the URL of the given page according to the permalink
+// pattern of the page's section. If there is no pattern for the section,
+// the pattern of the closest parent section is used. If there is no
+// pattern at all, href returns false.
+func (p permalinks) href(page *model.Page, filename string) (string, bool, error) {
+	pattern, ok := p.pattern(page.Route)
+	if !ok {
+		return "", false, nil
+	}
+
+	var err error
+
+	href := placeholderExpr.ReplaceAllStringFunc(pattern, func(placeholder string) string {
+		if err == nil && page.Date.IsZero() && (placeholder == ":year" || placeholder == ":month" || placeholder == ":day") {
+			err = fmt.Errorf("permalink pattern %s: placeholder %s requires a Date", pattern, placeholder)
+		}
+		return placeholders[placeholder](page, filename)
+	})
+
+	return path.Clean("/" + href), true, err
+}
+
+// pattern returns the permalink pattern for the given route.
+func (p permalinks) pattern(route string) (string, bool) {
+	route = path.Clean("/" + route)
+
+	for {
+		if pattern, ok := p[route]; ok {
+			return pattern, true
+		}
+		if route == "/" {
+			return "", false
+		}
+		route = path.Dir(route)
+	}
+}
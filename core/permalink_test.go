@@ -0,0 +1,101 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/verless/verless/model"
+	"github.com/verless/verless/test"
+)
+
+// TestNewPermalinks checks if unknown placeholders in permalink patterns
+// are rejected.
+func TestNewPermalinks(t *testing.T) {
+	tests := map[string]struct {
+		cfg           map[string]string
+		expectedError bool
+	}{
+		"valid patterns": {
+			cfg: map[string]string{
+				"blog": "/blog/:year/:month/:day/:slug",
+				"docs": "/:section/:title",
+			},
+		},
+		"unknown placeholder": {
+			cfg: map[string]string{
+				"blog": "/blog/:hour/:slug",
+			},
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		_, err := newPermalinks(testCase.cfg)
+		if testCase.expectedError {
+			test.Assert(t, err != nil, "unknown placeholders should return an error")
+			continue
+		}
+		test.Ok(t, err)
+	}
+}
+
+// TestPermalinks_href checks if the permalink pattern of the closest
+// section is applied to a page.
+func TestPermalinks_href(t *testing.T) {
+	permalinks, err := newPermalinks(map[string]string{
+		"blog": "/blog/:year/:month/:day/:slug/",
+		"/":    "/:section/:title",
+	})
+	test.Ok(t, err)
+
+	date := time.Date(2020, 3, 30, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		page          model.Page
+		filename      string
+		expected      string
+		expectedOk    bool
+		expectedError bool
+	}{
+		"section pattern": {
+			page:       model.Page{ID: "roasting", Route: "/blog", Date: date},
+			filename:   "coffee-roasting",
+			expected:   "/blog/2020/03/30/roasting",
+			expectedOk: true,
+		},
+		"parent section pattern": {
+			page:       model.Page{ID: "roasting", Route: "/blog/coffee", Date: date},
+			filename:   "coffee-roasting",
+			expected:   "/blog/2020/03/30/roasting",
+			expectedOk: true,
+		},
+		"root pattern": {
+			page:       model.Page{ID: "espresso", Route: "/docs/brewing", Title: "Brewing Espresso"},
+			filename:   "espresso",
+			expected:   "/docs/brewing-espresso",
+			expectedOk: true,
+		},
+		"missing date": {
+			page:          model.Page{ID: "roasting", Route: "/blog"},
+			filename:      "coffee-roasting",
+			expectedOk:    true,
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		href, ok, err := permalinks.href(&testCase.page, testCase.filename)
+		test.Equals(t, testCase.expectedOk, ok)
+
+		if testCase.expectedError {
+			test.Assert(t, err != nil, "date placeholders without a Date should return an error")
+			continue
+		}
+		test.Ok(t, err)
+		test.Equals(t, testCase.expected, href)
+	}
+}
@@ -0,0 +1,143 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/verless/verless/config"
+	"github.com/verless/verless/fs"
+	"github.com/verless/verless/graph"
+	"github.com/verless/verless/out"
+	"github.com/verless/verless/out/style"
+	"github.com/verless/verless/theme"
+)
+
+// rebuilder rebuilds the parts of a project that are affected by a
+// changed file. It uses the dependency graph of the last successful
+// build to determine the kind of rebuild:
+//
+//   - Static files are copied into the target filesystem on their own.
+//     Resources of page bundles additionally trigger a partial build.
+//   - Content files and templates trigger a partial build of the existing
+//     build where only the changed content files are parsed and only
+//     outputs depending on them are rendered.
+//   - Configuration files and unknown files trigger a full build.
+type rebuilder struct {
+	fs      afero.Fs
+	path    string
+	theme   string
+	factory func() (*Build, error)
+	build   *Build
+}
+
+// rebuild rebuilds the project for the given changed file. Afterwards,
+// the rebuilder holds the new build.
+func (r *rebuilder) rebuild(file string) error {
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+
+	switch r.kind(file) {
+	case graph.Static:
+		if err := r.copyStatic(file); err != nil {
+			return err
+		}
+		// Resources of page bundles are listed in their page, so the page
+		// has to be rendered again if resources are added or removed.
+		if inDir(file, filepath.Join(r.path, config.ContentDir)) {
+			return r.run(map[string]bool{file: true})
+		}
+		return nil
+	case graph.Source, graph.Template:
+		return r.run(map[string]bool{file: true})
+	default:
+		return r.run(nil)
+	}
+}
+
+// kind determines the kind of the given file. If the file isn't part of
+// the dependency graph yet, its kind is inferred from its location.
+func (r *rebuilder) kind(file string) graph.Kind {
+	if kind := r.build.Graph.Kind(file); kind != graph.Unknown {
+		return kind
+	}
+
+	dirs := map[string]graph.Kind{
+		filepath.Join(r.path, config.ContentDir): graph.Source,
+		theme.TemplatePath(r.path, r.theme):      graph.Template,
+	}
+
+	for dir, kind := range dirs {
+		if inDir(file, dir) {
+			return kind
+		}
+	}
+
+	return graph.Config
+}
+
+// inDir determines whether the given absolute file path is located
+// inside the given directory.
+func inDir(file, dir string) bool {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(file, abs+string(filepath.Separator))
+}
+
+// copyStatic copies a changed static file to all of its destinations.
+// If the file has been removed, its destinations are removed as well.
+func (r *rebuilder) copyStatic(file string) error {
+	_, err := os.Stat(file)
+	removed := os.IsNotExist(err)
+
+	for _, dest := range r.build.Graph.Dependents(file) {
+		if removed {
+			if err := r.fs.Remove(dest); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		if err := fs.CopyFileFromOS(r.fs, file, dest); err != nil {
+			return err
+		}
+	}
+
+	out.T(style.HeavyCheckMark, "copied static file %s", filepath.Base(file))
+
+	return nil
+}
+
+// run runs a partial build where only the changed content files are read
+// and parsed and only affected outputs are rendered. The existing build
+// is reused for that. If changed is nil, run creates and runs a new build.
+func (r *rebuilder) run(changed map[string]bool) error {
+	build := r.build
+
+	if changed == nil {
+		var err error
+		if build, err = r.factory(); err != nil {
+			return err
+		}
+		// Parsers registered using RegisterParser aren't part of the
+		// configuration, so they have to be registered again.
+		for extension, parser := range r.build.Parsers {
+			build.RegisterParser(extension, parser)
+		}
+	}
+
+	build.changed = changed
+
+	if err := build.Run(); err != nil {
+		return err
+	}
+
+	r.build = build
+	printBuildSuccess(build)
+
+	return nil
+}
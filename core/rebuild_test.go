@@ -0,0 +1,78 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/verless/verless/graph"
+	"github.com/verless/verless/test"
+)
+
+const (
+	projectPath = "../example"
+)
+
+// TestRebuilder_rebuild checks if changed files trigger the expected
+// kind of rebuild and if only affected pages are rendered again.
+func TestRebuilder_rebuild(t *testing.T) {
+	memMapFs := afero.NewMemMapFs()
+	options := BuildOptions{
+		OutputDir: "../rebuild-test-out",
+		Overwrite: true,
+	}
+
+	factory := func() (*Build, error) {
+		return NewBuild(memMapFs, projectPath, options)
+	}
+
+	build, err := factory()
+	test.Ok(t, err)
+	test.Ok(t, build.Run())
+
+	r := rebuilder{
+		fs:      memMapFs,
+		path:    projectPath,
+		theme:   "default",
+		factory: factory,
+		build:   build,
+	}
+
+	tests := map[string]struct {
+		file string
+		kind graph.Kind
+		full bool
+	}{
+		"static file": {
+			file: filepath.Join(projectPath, "static", "img", "espresso.jpg"),
+			kind: graph.Static,
+		},
+		"content file": {
+			file: filepath.Join(projectPath, "content", "about.md"),
+			kind: graph.Source,
+		},
+		"config file": {
+			file: filepath.Join(projectPath, "verless.yml"),
+			kind: graph.Config,
+			full: true,
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		abs, err := filepath.Abs(testCase.file)
+		test.Ok(t, err)
+
+		test.Equals(t, testCase.kind, r.kind(abs))
+
+		prev := r.build
+		test.Ok(t, r.rebuild(testCase.file))
+		test.Equals(t, testCase.full, prev != r.build)
+
+		// Nothing has actually changed, so all pages have to be reused.
+		if testCase.kind != graph.Static {
+			test.Equals(t, 0, r.build.Stats().Rebuilt)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/verless/verless/model"
+)
+
+// redirects holds the redirects for all page aliases of the current
+// build. It is safe for concurrent usage, so that the redirects can be
+// updated after each rebuild while serving the website.
+type redirects struct {
+	targets map[string]string
+	mutex   sync.RWMutex
+}
+
+// newRedirects creates a new redirects instance for the given redirects.
+func newRedirects(list []model.Redirect) *redirects {
+	r := redirects{}
+	r.set(list)
+	return &r
+}
+
+// set replaces all redirects with the given redirects.
+func (r *redirects) set(list []model.Redirect) {
+	targets := make(map[string]string, len(list))
+	for _, redirect := range list {
+		targets[redirect.From] = redirect.To
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.targets = targets
+}
+
+// target returns the redirect target for the given URL path. A trailing
+// slash in the path is ignored.
+func (r *redirects) target(path string) (string, bool) {
+	if path != "/" {
+		path = strings.TrimSuffix(path, "/")
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	target, ok := r.targets[path]
+	return target, ok
+}
+
+// withRedirects responds with a permanent redirect for all requests to a
+// page alias. All other requests are passed to the next handler.
+func withRedirects(redirects *redirects, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if target, ok := redirects.target(r.URL.Path); ok {
+			http.Redirect(w, r, target, http.StatusMovedPermanently)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
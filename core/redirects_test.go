@@ -0,0 +1,62 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/verless/verless/model"
+	"github.com/verless/verless/test"
+)
+
+// TestWithRedirects checks if requests to page aliases are redirected
+// permanently and if all other requests are passed to the next handler.
+func TestWithRedirects(t *testing.T) {
+	redirects := newRedirects([]model.Redirect{
+		{From: "/old-post", To: "/blog/new-post"},
+	})
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler := withRedirects(redirects, next)
+
+	tests := map[string]struct {
+		path             string
+		expectedCode     int
+		expectedLocation string
+	}{
+		"alias": {
+			path:             "/old-post",
+			expectedCode:     http.StatusMovedPermanently,
+			expectedLocation: "/blog/new-post",
+		},
+		"alias with trailing slash": {
+			path:             "/old-post/",
+			expectedCode:     http.StatusMovedPermanently,
+			expectedLocation: "/blog/new-post",
+		},
+		"page": {
+			path:         "/blog/new-post",
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, testCase.path, nil))
+
+		test.Equals(t, testCase.expectedCode, recorder.Code)
+		test.Equals(t, testCase.expectedLocation, recorder.Header().Get("Location"))
+	}
+
+	// Updated redirects have to be used immediately.
+	redirects.set(nil)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/old-post", nil))
+	test.Equals(t, http.StatusOK, recorder.Code)
+}
@@ -57,6 +57,7 @@ func Serve(path string, options ServeOptions) error {
 				targetFiles,
 				filepath.Join(path, config.StaticDir, config.GeneratedDir),
 				theme.GeneratedPath(path, cfg.Theme),
+				filepath.Join(path, config.CacheDir),
 			},
 			Path:      path,
 			ChangedCh: rebuildCh,
@@ -77,14 +78,25 @@ func Serve(path string, options ServeOptions) error {
 		return err
 	}
 
-	out.T(style.HeavyCheckMark, "project built successfully")
+	printBuildSuccess(build)
+
+	redirects := newRedirects(build.Redirects())
+
+	var reload *liveReload
 
 	// If --watch is enabled, launch a goroutine that handles rebuilds.
 	if options.Watch {
-		factory := func() (*Build, error) {
-			return NewBuild(memMapFs, path, options.BuildOptions)
+		reload = newLiveReload()
+		r := rebuilder{
+			fs:    memMapFs,
+			path:  path,
+			theme: cfg.Theme,
+			factory: func() (*Build, error) {
+				return NewBuild(memMapFs, path, options.BuildOptions)
+			},
+			build: build,
 		}
-		go watchAndRebuild(factory, rebuildCh, done)
+		go watchAndRebuild(&r, reload, redirects, rebuildCh, done)
 	}
 
 	// If the target folder doesn't exist, return an error.
@@ -92,43 +104,63 @@ func Serve(path string, options ServeOptions) error {
 		return err
 	}
 
-	err = listenAndServe(memMapFs, targetFiles, options.IP, options.Port)
+	err = listenAndServe(memMapFs, targetFiles, reload, redirects, options.IP, options.Port)
 	close(done)
 
 	return err
 }
 
-// watchAndRebuild watches the project for changes and rebuilds the project
-// once a change is detected. Any errors will be printed directly.
-func watchAndRebuild(factory func() (*Build, error), rebuildCh <-chan string, doneCh <-chan bool) {
+// watchAndRebuild watches the project for changes and rebuilds the parts
+// of the project affected by a change once it is detected. After each
+// successful rebuild, the redirects are updated and all open pages get
+// reloaded. Any errors will be printed directly and displayed as an
+// overlay on all open pages until the next successful rebuild.
+func watchAndRebuild(r *rebuilder, reload *liveReload, redirects *redirects, rebuildCh <-chan string, doneCh <-chan bool) {
 	for {
 		select {
-		case _, ok := <-rebuildCh:
+		case file, ok := <-rebuildCh:
 			if !ok {
 				return
 			}
 			out.T(style.Sparkles, "rebuilding project ...")
 
-			build, err := factory()
-			if err != nil {
-				out.Err(style.Exclamation, "failed to initialize new build: %s", err.Error())
+			if err := r.rebuild(file); err != nil {
+				out.Err(style.Exclamation, "failed to build the project: %s", err.Error())
+				reload.overlay.set(err)
+				reload.notify(reloadEvent)
 				continue
 			}
 
-			if err := build.Run(); err != nil {
-				out.Err(style.Exclamation, "failed to build the project: %s", err.Error())
-				continue
+			redirects.set(r.build.Redirects())
+
+			// Reload the entire page if it still displays the error
+			// overlay of the previous build.
+			event := eventFor(file)
+			if reload.overlay.failed() {
+				event = reloadEvent
 			}
 
-			out.T(style.HeavyCheckMark, "project built successfully")
+			reload.overlay.set(nil)
+			reload.notify(event)
 		case _, _ = <-doneCh:
 			return
 		}
 	}
 }
 
-// listenAndServe starts a file server serving the built project.
-func listenAndServe(fs afero.Fs, path string, ip net.IP, port uint16) error {
+// printBuildSuccess prints a success message along with the cache
+// statistics of the given build.
+func printBuildSuccess(build *Build) {
+	stats := build.Stats()
+	out.T(style.HeavyCheckMark, "project built successfully (%d pages rebuilt, %d reused)",
+		stats.Rebuilt, stats.Reused)
+}
+
+// listenAndServe starts a file server serving the built project. Requests
+// to page aliases are redirected permanently. If a live reload is given,
+// the server provides the live reload event stream and injects the live
+// reload script into all HTML pages.
+func listenAndServe(fs afero.Fs, path string, reload *liveReload, redirects *redirects, ip net.IP, port uint16) error {
 	addr := fmt.Sprintf("%v:%v", ip, port)
 
 	if ip.To4() == nil {
@@ -136,13 +168,24 @@ func listenAndServe(fs afero.Fs, path string, ip net.IP, port uint16) error {
 	}
 
 	httpFs := afero.NewHttpFs(fs)
+	handler := http.FileServer(httpFs.Dir(path))
+
+	if reload != nil {
+		handler = withLiveReload(fs, path, reload, handler)
+	}
+
+	handler = withRedirects(redirects, handler)
 
 	server := http.Server{
 		Addr:    addr,
-		Handler: http.FileServer(httpFs.Dir(path)),
+		Handler: handler,
+	}
+
+	if reload != nil {
+		server.RegisterOnShutdown(reload.close)
 	}
 
-	shutdown := make(chan os.Signal)
+	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, os.Interrupt)
 
 	out.T(style.Bulb, "serving website on %s", addr)
@@ -0,0 +1,179 @@
+// Package data provides functions for loading global data files.
+package data
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v2"
+)
+
+// decoders maps all supported file extensions to a decode function.
+var decoders = map[string]func(src []byte) (interface{}, error){
+	".yml":  decodeYAML,
+	".yaml": decodeYAML,
+	".json": decodeJSON,
+	".toml": decodeTOML,
+	".csv":  decodeCSV,
+}
+
+// Load reads all data files inside the given directory and returns their
+// contents keyed by their path. For example, the contents of team.yml
+// will be stored as data["team"] and the contents of coffee/origins.csv
+// as data["coffee"]["origins"].
+//
+// Files with an unsupported extension are ignored. If the directory
+// doesn't exist, Load returns an empty map.
+func Load(dir string) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		decode, supported := decoders[strings.ToLower(filepath.Ext(path))]
+		if !supported {
+			return nil
+		}
+
+		src, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		value, err := decode(src)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		return insert(data, keys(rel), value)
+	})
+
+	return data, err
+}
+
+// keys converts a relative file path like coffee/origins.csv into the
+// keys the file will be stored under, like [coffee origins].
+func keys(rel string) []string {
+	rel = strings.TrimSuffix(rel, filepath.Ext(rel))
+	return strings.Split(filepath.ToSlash(rel), "/")
+}
+
+// insert stores a value in the nested data map under the given keys.
+func insert(data map[string]interface{}, keys []string, value interface{}) error {
+	for _, key := range keys[:len(keys)-1] {
+		next, exists := data[key]
+		if !exists {
+			next = make(map[string]interface{})
+			data[key] = next
+		}
+
+		nested, ok := next.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("data key %s is used by a file and a directory", key)
+		}
+		data = nested
+	}
+
+	last := keys[len(keys)-1]
+
+	if _, exists := data[last]; exists {
+		return fmt.Errorf("data key %s is used by multiple files", last)
+	}
+	data[last] = value
+
+	return nil
+}
+
+func decodeYAML(src []byte) (interface{}, error) {
+	var value interface{}
+	if err := yaml.Unmarshal(src, &value); err != nil {
+		return nil, err
+	}
+	return normalize(value), nil
+}
+
+func decodeJSON(src []byte) (interface{}, error) {
+	var value interface{}
+	if err := json.Unmarshal(src, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func decodeTOML(src []byte) (interface{}, error) {
+	var value map[string]interface{}
+	if err := toml.Unmarshal(src, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// decodeCSV decodes a CSV file with a header row. Each row is converted
+// to a map with the column names as keys.
+func decodeCSV(src []byte) (interface{}, error) {
+	records, err := csv.NewReader(bytes.NewReader(src)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]interface{}, 0, len(records))
+
+	if len(records) == 0 {
+		return rows, nil
+	}
+
+	header := records[0]
+
+	for _, record := range records[1:] {
+		row := make(map[string]interface{}, len(header))
+		for i, column := range header {
+			if i < len(record) {
+				row[column] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// normalize converts all maps with interface{} keys as returned by the
+// YAML decoder to maps with string keys, so that templates can index
+// them.
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = normalize(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalize(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
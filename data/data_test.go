@@ -0,0 +1,67 @@
+package data
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/verless/verless/test"
+)
+
+// TestLoad checks if all supported data files are loaded and stored
+// under keys matching their paths.
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "verless-data")
+	test.Ok(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	files := map[string]string{
+		"team.yml":           "members:\n  - name: Clara\n    role: Barista\n",
+		"pricing.json":       `{"plans": [{"name": "Espresso", "price": 2.5}]}`,
+		"shop.toml":          "name = \"Crema\"\n",
+		"coffee/origins.csv": "country,altitude\nEthiopia,2000\nBrazil,1100\n",
+		"coffee/ignored.txt": "This file is not loaded.",
+	}
+
+	for file, content := range files {
+		path := filepath.Join(dir, file)
+		test.Ok(t, os.MkdirAll(filepath.Dir(path), 0755))
+		test.Ok(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	data, err := Load(dir)
+	test.Ok(t, err)
+
+	expected := map[string]interface{}{
+		"team": map[string]interface{}{
+			"members": []interface{}{
+				map[string]interface{}{"name": "Clara", "role": "Barista"},
+			},
+		},
+		"pricing": map[string]interface{}{
+			"plans": []interface{}{
+				map[string]interface{}{"name": "Espresso", "price": 2.5},
+			},
+		},
+		"shop": map[string]interface{}{
+			"name": "Crema",
+		},
+		"coffee": map[string]interface{}{
+			"origins": []interface{}{
+				map[string]interface{}{"country": "Ethiopia", "altitude": "2000"},
+				map[string]interface{}{"country": "Brazil", "altitude": "1100"},
+			},
+		},
+	}
+
+	test.Equals(t, expected, data)
+}
+
+// TestLoad_missingDir checks if a missing data directory results in an
+// empty data map.
+func TestLoad_missingDir(t *testing.T) {
+	data, err := Load(filepath.Join(os.TempDir(), "verless-data-missing"))
+	test.Ok(t, err)
+	test.Equals(t, map[string]interface{}{}, data)
+}
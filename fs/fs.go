@@ -80,7 +80,10 @@ func StreamFiles(path string, files chan<- string, filters ...func(file string)
 // Rmdir removes an entire directory along with its contents. If the
 // directory does not exist, nothing happens.
 func Rmdir(targetFs afero.Fs, path string) error {
-	if _, err := targetFs.Stat(path); !os.IsNotExist(err) {
+	if _, err := targetFs.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
 		return err
 	}
 
@@ -93,6 +96,13 @@ func Rmdir(targetFs afero.Fs, path string) error {
 // If fileOnly is set to true, files will be copied directly into the
 // destination directory without their directory structure inside src.
 func CopyFromOS(targetFs afero.Fs, src, dest string, fileOnly bool) error {
+	return CopyFromOSFunc(targetFs, src, dest, fileOnly, nil)
+}
+
+// CopyFromOSFunc works like CopyFromOS, but additionally invokes the
+// copied function with the source and destination path of each copied
+// file. copied may be nil.
+func CopyFromOSFunc(targetFs afero.Fs, src, dest string, fileOnly bool, copied func(src, dest string)) error {
 	var (
 		files   = make(chan string)
 		errchan = make(chan error)
@@ -117,28 +127,40 @@ func CopyFromOS(targetFs afero.Fs, src, dest string, fileOnly bool) error {
 			destPath = filepath.ToSlash(filepath.Join(dest, file))
 		}
 
-		if err := targetFs.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		if err := CopyFileFromOS(targetFs, srcPath, destPath); err != nil {
 			return err
 		}
 
-		srcFile, err := os.Open(srcPath)
-		if err != nil {
-			return err
-		}
-		destFile, err := targetFs.Create(destPath)
-		if err != nil {
-			return err
+		if copied != nil {
+			copied(srcPath, destPath)
 		}
+	}
 
-		if _, err = io.Copy(destFile, srcFile); err != nil {
-			return err
-		}
+	err = <-errchan
+	return err
+}
 
-		_ = srcFile.Close()
-		_ = destFile.Close()
+// CopyFileFromOS copies a single file from the OS filesystem into another
+// filesystem instance. All directories of the destination path will be
+// created if they don't exist yet.
+func CopyFileFromOS(targetFs afero.Fs, src, dest string) error {
+	if err := targetFs.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
 	}
 
-	err = <-errchan
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = srcFile.Close() }()
+
+	destFile, err := targetFs.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = destFile.Close() }()
+
+	_, err = io.Copy(destFile, srcFile)
 	return err
 }
 
@@ -76,6 +76,8 @@ func TestRmdir(t *testing.T) {
 			t.Errorf("Rmdir() error = %v, wantErr %v", err, tt.wantErr)
 			test.ExpectedError(t, nil, err)
 		}
+		exists, _ := afero.Exists(tt.args.fs, tt.args.path)
+		test.Equals(t, false, exists)
 	}
 }
 
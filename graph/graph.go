@@ -0,0 +1,91 @@
+// Package graph provides a dependency graph that links the files of a
+// verless project to the build outputs depending on them.
+//
+// When a file changes, the graph tells which outputs are affected by
+// that change. This allows a partial rebuild instead of building the
+// entire project again.
+package graph
+
+import (
+	"sort"
+	"sync"
+)
+
+// Kind represents the kind of a project file.
+type Kind int
+
+const (
+	// Unknown is the kind of files that haven't been added to the graph.
+	Unknown Kind = iota
+	// Config is the kind of configuration files. All outputs depend on
+	// configuration files.
+	Config
+	// Source is the kind of content files that are parsed to pages.
+	Source
+	// Template is the kind of template files that pages are rendered with.
+	Template
+	// Static is the kind of static files that are copied as they are.
+	Static
+)
+
+const (
+	// All is a dependent that stands for all outputs of a build.
+	All string = "*"
+)
+
+// Graph is a dependency graph consisting of files and their dependents.
+// It is safe for concurrent usage.
+type Graph struct {
+	kinds map[string]Kind
+	edges map[string]map[string]struct{}
+	mutex sync.RWMutex
+}
+
+// New creates a new, empty Graph instance.
+func New() *Graph {
+	g := Graph{
+		kinds: make(map[string]Kind),
+		edges: make(map[string]map[string]struct{}),
+	}
+	return &g
+}
+
+// Add registers a dependency of the given kind and adds an edge from the
+// dependency to the dependent, which typically is an output file.
+func (g *Graph) Add(kind Kind, dependency, dependent string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.kinds[dependency] = kind
+
+	if _, exists := g.edges[dependency]; !exists {
+		g.edges[dependency] = make(map[string]struct{})
+	}
+
+	g.edges[dependency][dependent] = struct{}{}
+}
+
+// Kind returns the kind of the given dependency. If the dependency has
+// not been added to the graph, Unknown is returned.
+func (g *Graph) Kind(dependency string) Kind {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	return g.kinds[dependency]
+}
+
+// Dependents returns the sorted dependents of the given dependency.
+func (g *Graph) Dependents(dependency string) []string {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	dependents := make([]string, 0, len(g.edges[dependency]))
+
+	for dependent := range g.edges[dependency] {
+		dependents = append(dependents, dependent)
+	}
+
+	sort.Strings(dependents)
+
+	return dependents
+}
@@ -0,0 +1,46 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/verless/verless/test"
+)
+
+// TestGraph_Dependents checks if all dependents of a dependency are
+// returned along with the dependency's kind.
+func TestGraph_Dependents(t *testing.T) {
+	tests := map[string]struct {
+		dependency string
+		kind       Kind
+		dependents []string
+	}{
+		"config file": {
+			dependency: "/project/verless.yml",
+			kind:       Config,
+			dependents: []string{All},
+		},
+		"template file": {
+			dependency: "/project/themes/default/templates/page.html",
+			kind:       Template,
+			dependents: []string{"/target/blog/a/index.html", "/target/blog/b/index.html"},
+		},
+		"unknown file": {
+			dependency: "/project/unknown.txt",
+			kind:       Unknown,
+			dependents: []string{},
+		},
+	}
+
+	g := New()
+	g.Add(Config, "/project/verless.yml", All)
+	g.Add(Template, "/project/themes/default/templates/page.html", "/target/blog/b/index.html")
+	g.Add(Template, "/project/themes/default/templates/page.html", "/target/blog/a/index.html")
+	g.Add(Template, "/project/themes/default/templates/page.html", "/target/blog/a/index.html")
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		test.Equals(t, testCase.kind, g.Kind(testCase.dependency))
+		test.Equals(t, testCase.dependents, g.Dependents(testCase.dependency))
+	}
+}
@@ -0,0 +1,12 @@
+package model
+
+// Language represents a language of a multilingual website.
+type Language struct {
+	// Code is the language code like de.
+	Code string
+	// Name is the display name of the language like Deutsch.
+	Name string
+	// Href is the URL of the language's home page, like /de. All URLs
+	// of the language's pages start with Href.
+	Href string
+}
@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"html/template"
+	"strings"
+	"time"
+)
 
 const (
 	customListPageID string = "index"
@@ -24,21 +28,43 @@ type Page struct {
 	Route       string
 	ID          string
 	Href        string
+	Slug        string
 	Title       string
 	Author      string
 	Date        time.Time
 	Tags        []Tag
+	Taxonomies  map[string][]Tag
 	Img         string
 	Credit      string
 	Description string
-	Content     string
+	Content     template.HTML
 	Related     []*Page
+	Resources   []Resource
+	Aliases     []string
 	Type        *Type
 	Hidden      bool
+	// Bundle indicates that the page's index file opts in to turning its
+	// directory into a page bundle.
+	Bundle      bool
+	Weight      int
+	Draft       bool
+	PublishDate time.Time
+	ExpiryDate  time.Time
 	Meta        map[string]string
+	// Language is the code of the page's language on multilingual
+	// websites, like de.
+	Language string
+	// Translations contains the same page in all other languages.
+	Translations []*Page
+	// Params contains all front matter fields with their full structure,
+	// including default values declared by the page type.
+	Params map[string]interface{}
 
 	providedRelated []string
 	providedType    string
+	sourcePath      string
+	sourceHash      string
+	contentPath     string
 }
 
 // IsCustomListPage returns whether the page is a custom list page that has
@@ -47,6 +73,23 @@ func (p *Page) IsCustomListPage() bool {
 	return p.ID == customListPageID
 }
 
+// IsFuture returns whether the page is scheduled for publication after
+// the given point in time. If the page has no publish date, its date is
+// used instead.
+func (p *Page) IsFuture(now time.Time) bool {
+	publishDate := p.PublishDate
+	if publishDate.IsZero() {
+		publishDate = p.Date
+	}
+	return publishDate.After(now)
+}
+
+// IsExpired returns whether the page has expired at the given point in
+// time. Pages without an expiry date never expire.
+func (p *Page) IsExpired(now time.Time) bool {
+	return !p.ExpiryDate.IsZero() && !p.ExpiryDate.After(now)
+}
+
 // ProvidedRelated returns all Fully Qualified Name URIs related to the page.
 func (p *Page) ProvidedRelated() []string {
 	return p.providedRelated
@@ -67,14 +110,74 @@ func (p *Page) SetProvidedType(providedType string) {
 	p.providedType = providedType
 }
 
+// Param returns the value of the front matter field with the given name,
+// including default values declared by the page type. The name is case-
+// insensitive. If there is no such field, Param returns nil.
+func (p *Page) Param(name string) interface{} {
+	for key, val := range p.Params {
+		if strings.EqualFold(key, name) {
+			return val
+		}
+	}
+	return nil
+}
+
+// ContentPath returns the path of the page inside the content directory
+// without file extension, like /blog/making-espresso. It is the same as
+// Href unless the page has a slug or a permalink pattern applies.
+func (p *Page) ContentPath() string {
+	return p.contentPath
+}
+
+// SetContentPath sets the path of the page inside the content directory.
+func (p *Page) SetContentPath(contentPath string) {
+	p.contentPath = contentPath
+}
+
+// SourcePath returns the path of the file the page has been parsed from.
+func (p *Page) SourcePath() string {
+	return p.sourcePath
+}
+
+// SourceHash returns the hash of the file the page has been parsed from.
+func (p *Page) SourceHash() string {
+	return p.sourceHash
+}
+
+// SetSource sets the path and hash of the file the page has been parsed
+// from. Both are used for detecting whether the page has changed.
+func (p *Page) SetSource(path, hash string) {
+	p.sourcePath = path
+	p.sourceHash = hash
+}
+
 // ListPage represents an overview page that is generated for
 // each content sub-directory.
 type ListPage struct {
 	Page
 	Pages []*Page
+	// Taxonomy is the name of the taxonomy, like tags, if the list page
+	// lists the pages of a taxonomy term.
+	Taxonomy string
 }
 
 // Type represents a page type.
 type Type struct {
 	Template string
+	// Fields declares the front matter fields of pages with this type.
+	// Field names are case-insensitive.
+	Fields map[string]Field
+}
+
+// Field represents the schema of a front matter field.
+type Field struct {
+	// Type is the expected type of the value: string, number, bool,
+	// date, list or enum. If Type is empty, any value is allowed.
+	Type string
+	// Required specifies that the field has to be provided.
+	Required bool
+	// Default is the value used if the field hasn't been provided.
+	Default interface{}
+	// Values contains all allowed values if Type is enum.
+	Values []string
 }
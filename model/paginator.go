@@ -0,0 +1,100 @@
+package model
+
+import (
+	"path"
+	"strconv"
+)
+
+const (
+	// PaginationDir is the directory containing the second and all
+	// following pages of a paginated list page, like /blog/page/2.
+	PaginationDir string = "page"
+)
+
+// Paginator represents a single page of a paginated list page.
+type Paginator struct {
+	// PageNumber is the number of the current page, starting at 1.
+	PageNumber int
+	// TotalPages is the total number of pages.
+	TotalPages int
+	// PageSize is the maximum number of items per page.
+	PageSize int
+	// TotalItems is the total number of items across all pages.
+	TotalItems int
+	// Href is the URL of the current page.
+	Href string
+	// PrevHref is the URL of the previous page. It is empty for the
+	// first page.
+	PrevHref string
+	// NextHref is the URL of the next page. It is empty for the last
+	// page.
+	NextHref string
+	// Pages contains the items of the current page.
+	Pages []*Page
+}
+
+// HasPrev indicates whether there is a previous page.
+func (p *Paginator) HasPrev() bool {
+	return p.PrevHref != ""
+}
+
+// HasNext indicates whether there is a next page.
+func (p *Paginator) HasNext() bool {
+	return p.NextHref != ""
+}
+
+// Paginate splits the given pages of the list page with the given route
+// into chunks of pageSize pages. If pageSize is 0 or less, all pages are
+// put onto a single page. There is always at least one page.
+func Paginate(route string, pages []*Page, pageSize int) []Paginator {
+	if pageSize <= 0 {
+		pageSize = len(pages)
+	}
+
+	totalPages := 1
+	if len(pages) > 0 {
+		totalPages = (len(pages) + pageSize - 1) / pageSize
+	}
+
+	paginators := make([]Paginator, totalPages)
+
+	for i := range paginators {
+		start := i * pageSize
+		end := start + pageSize
+		if end > len(pages) {
+			end = len(pages)
+		}
+
+		paginators[i] = Paginator{
+			PageNumber: i + 1,
+			TotalPages: totalPages,
+			PageSize:   pageSize,
+			TotalItems: len(pages),
+			Href:       PageHref(route, i+1),
+			Pages:      pages[start:end],
+		}
+
+		if i > 0 {
+			paginators[i].PrevHref = PageHref(route, i)
+		}
+		if i < totalPages-1 {
+			paginators[i].NextHref = PageHref(route, i+2)
+		}
+	}
+
+	return paginators
+}
+
+// PageHref returns the URL of the page with the given number of the list
+// page with the given route, like /blog/ for the first page and
+// /blog/page/2/ for the second page.
+func PageHref(route string, number int) string {
+	href := route
+	if number > 1 {
+		href = path.Join(route, PaginationDir, strconv.Itoa(number))
+	}
+	if href == "/" {
+		return href
+	}
+	return path.Join("/", href) + "/"
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/verless/verless/test"
+)
+
+func TestPaginate(t *testing.T) {
+	pages := []*Page{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}, {ID: "e"}}
+
+	tests := map[string]struct {
+		route    string
+		pages    []*Page
+		pageSize int
+		expected []Paginator
+		ids      [][]string
+	}{
+		"disabled": {
+			route:    "/blog",
+			pages:    pages,
+			pageSize: 0,
+			expected: []Paginator{
+				{PageNumber: 1, TotalPages: 1, PageSize: 5, TotalItems: 5, Href: "/blog/"},
+			},
+			ids: [][]string{{"a", "b", "c", "d", "e"}},
+		},
+		"no pages": {
+			route:    "/blog",
+			pages:    []*Page{},
+			pageSize: 2,
+			expected: []Paginator{
+				{PageNumber: 1, TotalPages: 1, PageSize: 2, TotalItems: 0, Href: "/blog/"},
+			},
+			ids: [][]string{{}},
+		},
+		"multiple pages": {
+			route:    "/blog",
+			pages:    pages,
+			pageSize: 2,
+			expected: []Paginator{
+				{PageNumber: 1, TotalPages: 3, PageSize: 2, TotalItems: 5, Href: "/blog/",
+					NextHref: "/blog/page/2/"},
+				{PageNumber: 2, TotalPages: 3, PageSize: 2, TotalItems: 5, Href: "/blog/page/2/",
+					PrevHref: "/blog/", NextHref: "/blog/page/3/"},
+				{PageNumber: 3, TotalPages: 3, PageSize: 2, TotalItems: 5, Href: "/blog/page/3/",
+					PrevHref: "/blog/page/2/"},
+			},
+			ids: [][]string{{"a", "b"}, {"c", "d"}, {"e"}},
+		},
+		"root": {
+			route:    "/",
+			pages:    pages,
+			pageSize: 3,
+			expected: []Paginator{
+				{PageNumber: 1, TotalPages: 2, PageSize: 3, TotalItems: 5, Href: "/",
+					NextHref: "/page/2/"},
+				{PageNumber: 2, TotalPages: 2, PageSize: 3, TotalItems: 5, Href: "/page/2/",
+					PrevHref: "/"},
+			},
+			ids: [][]string{{"a", "b", "c"}, {"d", "e"}},
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		paginators := Paginate(testCase.route, testCase.pages, testCase.pageSize)
+		ids := make([][]string, len(paginators))
+
+		// Compare the pages by their IDs since pages contain unexported fields.
+		for i := range paginators {
+			ids[i] = []string{}
+			for _, page := range paginators[i].Pages {
+				ids[i] = append(ids[i], page.ID)
+			}
+			paginators[i].Pages = nil
+		}
+
+		test.Equals(t, testCase.expected, paginators)
+		test.Equals(t, testCase.ids, ids)
+	}
+}
+
+func TestPaginator_HasPrev(t *testing.T) {
+	paginators := Paginate("/blog", []*Page{{ID: "a"}, {ID: "b"}}, 1)
+
+	test.Equals(t, false, paginators[0].HasPrev())
+	test.Equals(t, true, paginators[0].HasNext())
+	test.Equals(t, true, paginators[1].HasPrev())
+	test.Equals(t, false, paginators[1].HasNext())
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"path"
+	"strings"
+)
+
+// Redirect represents a redirect from a page alias to the page.
+type Redirect struct {
+	// From is the alias, like /old-post.
+	From string
+	// To is the URL of the page, like /blog/new-post.
+	To string
+}
+
+// NormalizeAlias converts an alias like old-post/ to the form /old-post.
+func NormalizeAlias(alias string) string {
+	return path.Clean("/" + strings.TrimSpace(alias))
+}
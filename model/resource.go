@@ -0,0 +1,27 @@
+package model
+
+// Resource represents a file that is bundled with a page, like an image
+// stored next to the page's content file.
+type Resource struct {
+	// Name is the file name of the resource, like crema.jpg.
+	Name string
+	// Href is the URL path of the copied resource, like
+	// /blog/espresso/crema.jpg.
+	Href string
+
+	sourcePath string
+}
+
+// NewResource creates a new resource for the file stored in sourcePath.
+func NewResource(name, href, sourcePath string) Resource {
+	return Resource{
+		Name:       name,
+		Href:       href,
+		sourcePath: sourcePath,
+	}
+}
+
+// SourcePath returns the path of the resource's source file.
+func (r Resource) SourcePath() string {
+	return r.sourcePath
+}
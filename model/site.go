@@ -10,6 +10,19 @@ type Site struct {
 	Nav    Nav
 	Root   *Node
 	Footer Footer
+	// Data contains the contents of all data files keyed by their path.
+	Data map[string]interface{}
+	// Redirects contains a redirect for each page alias, sorted by the
+	// alias.
+	Redirects []Redirect
+	// Language is the language of the site. Multilingual websites consist
+	// of a separate site for each language.
+	Language Language
+	// Languages contains all languages of a multilingual website, the
+	// default language first.
+	Languages []Language
+	// I18n contains the translated strings of the site's language.
+	I18n map[string]interface{}
 }
 
 // NewSite creates a new, fully initialized Site instance.
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// SortByDate sorts pages by their date.
+	SortByDate string = "date"
+	// SortByWeight sorts pages by their weight.
+	SortByWeight string = "weight"
+	// SortByTitle sorts pages by their title.
+	SortByTitle string = "title"
+
+	// SortAsc sorts pages in ascending order.
+	SortAsc string = "asc"
+	// SortDesc sorts pages in descending order.
+	SortDesc string = "desc"
+)
+
+// SortPages sorts the given pages by the given field in the given order.
+// If by is empty, the pages are sorted by date. If order is empty, the
+// default order for the field is used, which is descending for dates and
+// ascending for all other fields. Pages that are equal with respect to
+// the field are sorted by their Href, so that the order is stable.
+func SortPages(pages []*Page, by, order string) error {
+	by = strings.ToLower(by)
+	order = strings.ToLower(order)
+
+	if by == "" {
+		by = SortByDate
+	}
+
+	if order == "" {
+		order = SortAsc
+		if by == SortByDate {
+			order = SortDesc
+		}
+	}
+
+	var compare func(a, b *Page) int
+
+	switch by {
+	case SortByDate:
+		compare = func(a, b *Page) int {
+			switch {
+			case a.Date.Before(b.Date):
+				return -1
+			case a.Date.After(b.Date):
+				return 1
+			}
+			return 0
+		}
+	case SortByWeight:
+		compare = func(a, b *Page) int {
+			return a.Weight - b.Weight
+		}
+	case SortByTitle:
+		compare = func(a, b *Page) int {
+			return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+		}
+	default:
+		return fmt.Errorf("invalid sort field %s: expected one of %s, %s, %s", by, SortByDate, SortByWeight, SortByTitle)
+	}
+
+	if order != SortAsc && order != SortDesc {
+		return fmt.Errorf("invalid sort order %s: expected one of %s, %s", order, SortAsc, SortDesc)
+	}
+
+	sort.SliceStable(pages, func(i, j int) bool {
+		c := compare(pages[i], pages[j])
+		if c == 0 {
+			return pages[i].Href < pages[j].Href
+		}
+		if order == SortDesc {
+			return c > 0
+		}
+		return c < 0
+	})
+
+	return nil
+}
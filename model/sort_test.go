@@ -0,0 +1,73 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/verless/verless/test"
+)
+
+func TestSortPages(t *testing.T) {
+	tests := map[string]struct {
+		by            string
+		order         string
+		expected      []string
+		expectedError bool
+	}{
+		"default": {
+			expected: []string{"/b", "/a", "/c"},
+		},
+		"date ascending": {
+			by:       SortByDate,
+			order:    SortAsc,
+			expected: []string{"/a", "/c", "/b"},
+		},
+		"weight": {
+			by:       SortByWeight,
+			expected: []string{"/a", "/c", "/b"},
+		},
+		"weight descending": {
+			by:       SortByWeight,
+			order:    SortDesc,
+			expected: []string{"/b", "/a", "/c"},
+		},
+		"title": {
+			by:       "Title",
+			expected: []string{"/a", "/c", "/b"},
+		},
+		"invalid field": {
+			by:            "author",
+			expectedError: true,
+		},
+		"invalid order": {
+			order:         "random",
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		// The pages are listed in reverse order of their Href so that the
+		// tie-breaker is actually being tested.
+		pages := []*Page{
+			{Href: "/c", Title: "Cappuccino", Weight: 1, Date: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{Href: "/b", Title: "latte", Weight: 2, Date: time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)},
+			{Href: "/a", Title: "Americano", Weight: 1, Date: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		}
+
+		err := SortPages(pages, testCase.by, testCase.order)
+		if testCase.expectedError {
+			test.Assert(t, err != nil, "invalid sort options should return an error")
+			continue
+		}
+		test.Ok(t, err)
+
+		hrefs := make([]string, len(pages))
+		for i, p := range pages {
+			hrefs[i] = p.Href
+		}
+
+		test.Equals(t, testCase.expected, hrefs)
+	}
+}
@@ -0,0 +1,227 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	// yamlDelimiter encloses YAML front matter.
+	yamlDelimiter = []byte("---")
+	// tomlDelimiter encloses TOML front matter.
+	tomlDelimiter = []byte("+++")
+	// jsonStart opens JSON front matter.
+	jsonStart = []byte("{")
+
+	// yamlLineExpr matches the line number inside YAML errors, for
+	// example `yaml: line 3: did not find expected key`.
+	yamlLineExpr = regexp.MustCompile(`yaml: line (\d+)`)
+)
+
+// SyntaxError represents malformed front matter.
+type SyntaxError struct {
+	// Format is the front matter format: yaml, toml or json.
+	Format string
+	// Line is the line inside the content file that caused the error.
+	// It is 0 if the line is unknown.
+	Line int
+	// Err is the actual error returned by the decoder.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid %s front matter: %s", e.Format, e.Err)
+}
+
+// Unwrap returns the actual error returned by the decoder.
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// decodeFrontMatter decodes TOML front matter enclosed by +++ lines or
+// a JSON object followed by a line break at the beginning of src. It
+// returns the decoded metadata along with the remaining content.
+//
+// As content may start with a curly brace as well, like {name} is ..., a
+// leading curly brace only opens JSON front matter if the object decodes
+// successfully. If the first line consists of { only, the JSON object is
+// always considered as front matter and syntax errors are reported.
+//
+// If src doesn't start with TOML or JSON front matter, decodeFrontMatter
+// returns nil and src unchanged, so that YAML front matter can be decoded
+// by the Markdown parser itself.
+func decodeFrontMatter(src []byte) (metadata, []byte, error) {
+	switch {
+	case isDelimiterLine(src, tomlDelimiter):
+		return decodeDelimited(src, tomlDelimiter, "toml", toml.Unmarshal)
+	case isDelimiterLine(src, jsonStart):
+		return decodeJSON(src)
+	case bytes.HasPrefix(src, jsonStart):
+		if m, content, err := decodeJSON(src); err == nil {
+			return m, content, nil
+		}
+		return nil, src, nil
+	default:
+		return nil, src, nil
+	}
+}
+
+// decodeAnyFrontMatter decodes front matter like decodeFrontMatter, but
+// also decodes YAML front matter enclosed by --- lines. It is used by
+// parsers that can't rely on goldmark-meta for decoding YAML.
+func decodeAnyFrontMatter(src []byte) (metadata, []byte, error) {
+	if isDelimiterLine(src, yamlDelimiter) {
+		return decodeDelimited(src, yamlDelimiter, "yaml", yaml.Unmarshal)
+	}
+	return decodeFrontMatter(src)
+}
+
+// isDelimiterLine determines whether the first line of src consists of
+// the given delimiter only.
+func isDelimiterLine(src, delimiter []byte) bool {
+	line := src
+	if i := bytes.IndexByte(src, '\n'); i != -1 {
+		line = src[:i]
+	}
+	return bytes.Equal(bytes.TrimSpace(line), delimiter)
+}
+
+// decodeDelimited decodes front matter enclosed by the given delimiter
+// lines using the given unmarshal function.
+func decodeDelimited(src, delimiter []byte, format string, unmarshal func([]byte, interface{}) error) (metadata, []byte, error) {
+	start := bytes.IndexByte(src, '\n')
+	if start == -1 {
+		start = len(src) - 1
+	}
+
+	block, content, ok := splitAt(src[start+1:], delimiter)
+	if !ok {
+		return nil, nil, &SyntaxError{Format: format, Line: 1, Err: fmt.Errorf("missing closing %s", delimiter)}
+	}
+
+	m := make(map[string]interface{})
+
+	if err := unmarshal(block, &m); err != nil {
+		// The front matter block starts after the opening delimiter.
+		syntaxErr := SyntaxError{Format: format, Err: err}
+		if line := errorLine(err); line > 0 {
+			syntaxErr.Line = line + 1
+		}
+		return nil, nil, &syntaxErr
+	}
+
+	return normalize(m).(map[string]interface{}), content, nil
+}
+
+// decodeJSON decodes the JSON object at the beginning of src. The object
+// has to be followed by a line break or the end of src.
+func decodeJSON(src []byte) (metadata, []byte, error) {
+	var (
+		m       map[string]interface{}
+		decoder = json.NewDecoder(bytes.NewReader(src))
+	)
+
+	if err := decoder.Decode(&m); err != nil {
+		syntaxErr := SyntaxError{Format: "json", Err: err}
+		if jsonErr, ok := err.(*json.SyntaxError); ok {
+			syntaxErr.Line = bytes.Count(src[:jsonErr.Offset], []byte("\n")) + 1
+		}
+		return nil, nil, &syntaxErr
+	}
+
+	offset := int(decoder.InputOffset())
+	content := src[offset:]
+
+	if rest := bytes.TrimLeft(content, " \t\r"); len(rest) > 0 && rest[0] != '\n' {
+		return nil, nil, &SyntaxError{
+			Format: "json",
+			Line:   bytes.Count(src[:offset], []byte("\n")) + 1,
+			Err:    errors.New("missing line break after closing }"),
+		}
+	}
+
+	return m, content, nil
+}
+
+// splitAt splits src at the first line consisting of the delimiter only.
+// It returns the content before and after that line.
+func splitAt(src, delimiter []byte) ([]byte, []byte, bool) {
+	offset := 0
+
+	for offset <= len(src) {
+		end := bytes.IndexByte(src[offset:], '\n')
+		if end == -1 {
+			end = len(src) - offset
+		}
+
+		line := src[offset : offset+end]
+		if bytes.Equal(bytes.TrimSpace(line), delimiter) {
+			rest := offset + end
+			if rest < len(src) {
+				rest++
+			}
+			return src[:offset], src[rest:], true
+		}
+
+		offset += end + 1
+	}
+
+	return nil, nil, false
+}
+
+// yamlError converts an error returned by the YAML decoder to a
+// SyntaxError.
+func yamlError(err error) *SyntaxError {
+	syntaxErr := SyntaxError{Format: "yaml", Err: err}
+
+	if line := errorLine(err); line > 0 {
+		// The YAML block starts after the opening delimiter.
+		syntaxErr.Line = line + 1
+	}
+
+	return &syntaxErr
+}
+
+// errorLine returns the line number inside a YAML or TOML decoding error.
+// If the error doesn't contain a line number, errorLine returns 0.
+func errorLine(err error) int {
+	if decodeErr, ok := err.(*toml.DecodeError); ok {
+		row, _ := decodeErr.Position()
+		return row
+	}
+
+	if matches := yamlLineExpr.FindStringSubmatch(err.Error()); len(matches) == 2 {
+		if line, err := strconv.Atoi(matches[1]); err == nil {
+			return line
+		}
+	}
+
+	return 0
+}
+
+// normalizeDate converts TOML dates and times to strings, so that they
+// can be read the same way as dates in YAML and JSON front matter. All
+// other values are returned as they are.
+func normalizeDate(val interface{}) interface{} {
+	switch v := val.(type) {
+	case toml.LocalDate:
+		return v.String()
+	case toml.LocalDateTime:
+		return v.AsTime(time.UTC).Format(time.RFC3339)
+	case toml.LocalTime:
+		return v.String()
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,134 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/verless/verless/model"
+	"github.com/verless/verless/test"
+)
+
+// TestMarkdown_ParsePage_frontMatterFormats checks if YAML, TOML and JSON
+// front matter are decoded into the same page fields.
+func TestMarkdown_ParsePage_frontMatterFormats(t *testing.T) {
+	parser := NewMarkdown()
+
+	tests := map[string]string{
+		"yaml": `---
+Title: Coffee Roasting Basics
+Date: 2020-03-30
+Tags:
+    - Coffee
+Hidden: true
+---
+
+This is a blog post.`,
+		"toml": `+++
+Title = "Coffee Roasting Basics"
+Date = 2020-03-30
+Tags = ["Coffee"]
+Hidden = true
++++
+
+This is a blog post.`,
+		"json": `{
+    "Title": "Coffee Roasting Basics",
+    "Date": "2020-03-30",
+    "Tags": ["Coffee"],
+    "Hidden": true
+}
+
+This is a blog post.`,
+		"json on a single line": `{"Title": "Coffee Roasting Basics", "Date": "2020-03-30", "Tags": ["Coffee"], "Hidden": true}
+
+This is a blog post.`,
+	}
+
+	for name, src := range tests {
+		t.Log(name)
+
+		page, err := parser.ParsePage([]byte(src))
+		test.Ok(t, err)
+
+		test.Equals(t, "Coffee Roasting Basics", page.Title)
+		test.Equals(t, time.Date(2020, 3, 30, 0, 0, 0, 0, time.UTC), page.Date)
+		test.Equals(t, []model.Tag{{Name: "Coffee"}}, page.Tags)
+		test.Equals(t, true, page.Hidden)
+		test.Equals(t, "<p>This is a blog post.</p>\n", string(page.Content))
+	}
+}
+
+// TestMarkdown_ParsePage_syntaxError checks if malformed front matter is
+// reported along with the line inside the file.
+func TestMarkdown_ParsePage_syntaxError(t *testing.T) {
+	parser := NewMarkdown()
+
+	tests := map[string]struct {
+		src    string
+		format string
+		line   int
+	}{
+		"yaml": {
+			src:    "---\nTitle: Coffee\nTags: [Coffee\n---\n",
+			format: "yaml",
+			line:   3,
+		},
+		"toml": {
+			src:    "+++\nTitle = \"Coffee\"\nDate = \n+++\n",
+			format: "toml",
+			line:   3,
+		},
+		"json": {
+			src:    "{\n    \"Title\": \"Coffee\",\n    \"Date\"\n}\n",
+			format: "json",
+			line:   4,
+		},
+		"json without line break": {
+			src:    "{\n    \"Title\": \"Coffee\"\n} Brewing coffee\n",
+			format: "json",
+			line:   3,
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		_, err := parser.ParsePage([]byte(testCase.src))
+
+		var syntaxErr *SyntaxError
+		test.Assert(t, errors.As(err, &syntaxErr), "expected SyntaxError, got %v", err)
+		test.Equals(t, testCase.format, syntaxErr.Format)
+		test.Equals(t, testCase.line, syntaxErr.Line)
+	}
+}
+
+// TestMarkdown_ParsePage_curlyBrace checks if content starting with a
+// curly brace isn't mistaken for JSON front matter unless it starts with
+// a valid JSON object followed by a line break.
+func TestMarkdown_ParsePage_curlyBrace(t *testing.T) {
+	parser := NewMarkdown()
+
+	tests := map[string]struct {
+		src     string
+		content string
+	}{
+		"inline object": {
+			src:     "{\"Title\": \"Coffee\"} is valid JSON.\n",
+			content: "<p>{&quot;Title&quot;: &quot;Coffee&quot;} is valid JSON.</p>\n",
+		},
+		"placeholder": {
+			src:     "{name} enjoys coffee.\n",
+			content: "<p>{name} enjoys coffee.</p>\n",
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		page, err := parser.ParsePage([]byte(testCase.src))
+		test.Ok(t, err)
+		test.Equals(t, "", page.Title)
+		test.Equals(t, testCase.content, string(page.Content))
+	}
+}
@@ -0,0 +1,27 @@
+package parser
+
+import (
+	"github.com/verless/verless/model"
+)
+
+// NewHTML initializes and returns a new HTML parser.
+func NewHTML() *html {
+	return &html{}
+}
+
+// html is an internal type that satisfies the build.Parser interface.
+// It reads the front matter and takes the remaining content as raw
+// HTML without any conversion.
+type html struct{}
+
+// ParsePage converts the byte contents of an HTML file to an instance
+// of model.Page. The front matter may be provided in all formats the
+// Markdown parser supports.
+func (h *html) ParsePage(src []byte) (model.Page, error) {
+	metadata, content, err := decodeAnyFrontMatter(src)
+	if err != nil {
+		return model.Page{}, err
+	}
+
+	return newPage(metadata, string(content))
+}
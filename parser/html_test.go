@@ -0,0 +1,24 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/verless/verless/test"
+)
+
+// TestHTML_ParsePage checks if the front matter of an HTML file is read
+// and the remaining content is kept as it is.
+func TestHTML_ParsePage(t *testing.T) {
+	parser := NewHTML()
+
+	page, err := parser.ParsePage([]byte(`---
+Title: Coffee Roasting Basics
+---
+<h2>Roasting</h2>
+<p>This is a <em>blog post</em>.</p>
+`))
+	test.Ok(t, err)
+
+	test.Equals(t, "Coffee Roasting Basics", page.Title)
+	test.Equals(t, "<h2>Roasting</h2>\n<p>This is a <em>blog post</em>.</p>\n", string(page.Content))
+}
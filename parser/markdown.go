@@ -27,23 +27,30 @@ type markdown struct {
 }
 
 // ParsePage converts the byte contents of a Markdown file to
-// an instance of model.Page.
+// an instance of model.Page. The front matter may be provided as
+// YAML enclosed by ---, as TOML enclosed by +++ or as JSON object.
 func (m *markdown) ParsePage(src []byte) (model.Page, error) {
 	var (
-		page model.Page
-		buf  bytes.Buffer
-		ctx  = parser.NewContext()
+		buf bytes.Buffer
+		ctx = parser.NewContext()
 	)
 
-	if err := m.gm.Convert(src, &buf, parser.WithContext(ctx)); err != nil {
-		return page, err
+	metadata, content, err := decodeFrontMatter(src)
+	if err != nil {
+		return model.Page{}, err
 	}
 
-	page.Content = buf.String()
-	page.Meta = make(map[string]string)
-	metadata := meta.Get(ctx)
+	if err := m.gm.Convert(content, &buf, parser.WithContext(ctx)); err != nil {
+		return model.Page{}, err
+	}
 
-	readMetadata(metadata, &page)
+	// If there is no TOML or JSON front matter, the YAML front matter
+	// has been decoded by goldmark-meta while converting the content.
+	if metadata == nil {
+		if metadata, err = meta.TryGet(ctx); err != nil {
+			return model.Page{}, yamlError(err)
+		}
+	}
 
-	return page, nil
+	return newPage(metadata, buf.String())
 }
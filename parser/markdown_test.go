@@ -34,11 +34,9 @@ This is a blog post.`,
 			tags: []model.Tag{
 				{
 					Name: "Coffee",
-					Href: "/tags/coffee",
 				},
 				{
 					Name: "Roasting",
-					Href: "/tags/roasting",
 				},
 			},
 			content: "<p>This is a blog post.</p>\n",
@@ -53,6 +51,38 @@ This is a blog post.`,
 
 		test.Equals(t, testCase.title, page.Title)
 		test.Equals(t, testCase.tags, page.Tags)
-		test.Equals(t, testCase.content, page.Content)
+		test.Equals(t, testCase.content, string(page.Content))
 	}
 }
+
+// TestMarkdown_ParsePage_params checks if all front matter fields are
+// available as params with their full structure.
+func TestMarkdown_ParsePage_params(t *testing.T) {
+	parser := NewMarkdown()
+
+	page, err := parser.ParsePage([]byte(`---
+Title: Tomato Soup
+Rating: 4.5
+Ingredients:
+    - Tomatoes
+    - Onions
+SEO:
+    Title: The best tomato soup
+    Keywords:
+        - soup
+---`))
+	test.Ok(t, err)
+
+	expected := map[string]interface{}{
+		"Title":       "Tomato Soup",
+		"Rating":      4.5,
+		"Ingredients": []interface{}{"Tomatoes", "Onions"},
+		"SEO": map[string]interface{}{
+			"Title":    "The best tomato soup",
+			"Keywords": []interface{}{"soup"},
+		},
+	}
+
+	test.Equals(t, expected, page.Params)
+	test.Equals(t, 4.5, page.Param("rating"))
+}
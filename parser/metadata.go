@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"fmt"
+	"html/template"
 	"strings"
 	"time"
 
@@ -17,122 +19,377 @@ type (
 	// metadata represents a set of metadata.
 	metadata map[string]interface{}
 
-	// assignFn is a function that takes a value and assigns
-	// that value to an enclosed struct field. For example:
-	//
-	//	setID := func(val interface{}) {
-	//		user.ID = val.(int)
-	//	}
-	//
-	//	func setter(setID assignFn) {
-	//		setID(1)
-	//	}
-	//
-	// Type-asserting val to int in a closure avoids that the
-	// setter function has to be re-written for each data type.
-	assignFn func(val interface{})
+	// metadataReader reads values from a metadata map and checks their
+	// types. Instead of failing on the first invalid value, it collects
+	// all errors so that they can be reported at once.
+	metadataReader struct {
+		metadata metadata
+		errs     MetadataErrors
+	}
 )
 
+// MetadataError represents an invalid value in the front matter of a
+// content file.
+type MetadataError struct {
+	// Key is the front matter key holding the invalid value.
+	Key string
+	// Expected is the expected type, like string or date.
+	Expected string
+	// Actual is the actual type of the value. It is empty if a required
+	// value is missing.
+	Actual string
+	// Err is an optional error that occurred while converting the value,
+	// for example when parsing a date.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *MetadataError) Error() string {
+	if e.Actual == "" {
+		return fmt.Sprintf("missing required key %s of type %s", e.Key, e.Expected)
+	}
+	msg := fmt.Sprintf("invalid value for key %s: expected %s, got %s", e.Key, e.Expected, e.Actual)
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s (%s)", msg, e.Err)
+	}
+	return msg
+}
+
+// NewMetadataError returns a MetadataError for a front matter value that
+// doesn't have the expected type.
+func NewMetadataError(key, expected string, actual interface{}) *MetadataError {
+	return &MetadataError{
+		Key:      key,
+		Expected: expected,
+		Actual:   typeName(actual),
+	}
+}
+
+// Unwrap returns the error that occurred while converting the value.
+func (e *MetadataError) Unwrap() error {
+	return e.Err
+}
+
+// MetadataErrors is a collection of all invalid values in the front
+// matter of a content file.
+type MetadataErrors []*MetadataError
+
+// Error implements the error interface.
+func (e MetadataErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// newPage creates a new page with the given content and assigns the
+// values of the metadata map to its fields.
+func newPage(metadata metadata, content string) (model.Page, error) {
+	page := model.Page{
+		Content: template.HTML(content),
+		Meta:    make(map[string]string),
+	}
+
+	if err := readMetadata(metadata, &page); err != nil {
+		return page, err
+	}
+
+	return page, nil
+}
+
 // readMetadata reads values from a metadata map and assigns the
-// values to the fields of a model.Page instance.
-func readMetadata(metadata metadata, page *model.Page) {
-	readPrimitive(metadata["Title"], func(val interface{}) {
-		page.Title = val.(string)
+// values to the fields of a model.Page instance. If any value has an
+// invalid type, readMetadata returns a MetadataErrors instance.
+func readMetadata(metadata metadata, page *model.Page) error {
+	r := metadataReader{
+		metadata: metadata,
+	}
+
+	r.readString("Title", func(val string) {
+		page.Title = val
+	})
+
+	r.readString("Slug", func(val string) {
+		page.Slug = val
+	})
+
+	r.readString("Author", func(val string) {
+		page.Author = val
+	})
+
+	r.readDate("Date", func(val time.Time) {
+		page.Date = val
+	})
+
+	// The URLs of the tags are assigned by the tags taxonomy.
+	r.readList("Tags", func(val string) {
+		page.Tags = append(page.Tags, model.Tag{Name: val})
+	})
+
+	r.readString("Img", func(val string) {
+		page.Img = val
+	})
+
+	r.readString("Credit", func(val string) {
+		page.Credit = val
 	})
 
-	readPrimitive(metadata["Author"], func(val interface{}) {
-		page.Author = val.(string)
+	r.readString("Description", func(val string) {
+		page.Description = val
 	})
 
-	readDate(metadata["Date"], func(val interface{}) {
-		page.Date = val.(time.Time)
+	r.readList("Related", func(val string) {
+		page.AddProvidedRelated(val)
 	})
 
-	readList(metadata["Tags"], func(val interface{}) {
-		name := val.(string)
-		page.Tags = append(page.Tags, model.Tag{
-			Name: name,
-			Href: "/tags/" + strings.ToLower(name),
-		})
+	r.readList("Aliases", func(val string) {
+		page.Aliases = append(page.Aliases, model.NormalizeAlias(val))
 	})
 
-	readPrimitive(metadata["Img"], func(val interface{}) {
-		page.Img = val.(string)
+	r.readString("Type", func(val string) {
+		page.SetProvidedType(val)
 	})
 
-	readPrimitive(metadata["Credit"], func(val interface{}) {
-		page.Credit = val.(string)
+	r.readBool("Hidden", func(val bool) {
+		page.Hidden = val
 	})
 
-	readPrimitive(metadata["Description"], func(val interface{}) {
-		page.Description = val.(string)
+	r.readBool("Bundle", func(val bool) {
+		page.Bundle = val
 	})
 
-	readList(metadata["Related"], func(val interface{}) {
-		page.AddProvidedRelated(val.(string))
+	r.readInt("Weight", func(val int) {
+		page.Weight = val
 	})
 
-	readPrimitive(metadata["Type"], func(val interface{}) {
-		page.SetProvidedType(val.(string))
+	r.readBool("Draft", func(val bool) {
+		page.Draft = val
 	})
 
-	readPrimitive(metadata["Hidden"], func(val interface{}) {
-		page.Hidden = val.(bool)
+	r.readDate("PublishDate", func(val time.Time) {
+		page.PublishDate = val
 	})
-	readMap(metadata["Meta"], func(key, val interface{}) {
-		page.Meta[key.(string)] = val.(string)
+
+	r.readDate("ExpiryDate", func(val time.Time) {
+		page.ExpiryDate = val
+	})
+
+	r.readMap("Meta", func(key, val string) {
+		page.Meta[key] = val
 	})
+
+	page.Params = normalize(metadata).(map[string]interface{})
+
+	if len(r.errs) > 0 {
+		return r.errs
+	}
+
+	return nil
+}
+
+// readString converts a field to a string and invokes the assign
+// function with that string.
+func (r *metadataReader) readString(key string, assign func(val string)) {
+	field, ok := r.metadata[key]
+	if !ok || field == nil {
+		return
+	}
+
+	val, ok := field.(string)
+	if !ok {
+		r.fail(key, "string", field, nil)
+		return
+	}
+
+	assign(val)
 }
 
-// readPrimitive converts a field to a primitive value and invokes
-// the assignFn with that value.
-func readPrimitive(field interface{}, assign assignFn) {
-	if field == nil {
+// readBool converts a field to a bool and invokes the assign function
+// with that bool.
+func (r *metadataReader) readBool(key string, assign func(val bool)) {
+	field, ok := r.metadata[key]
+	if !ok || field == nil {
 		return
 	}
 
-	assign(field)
+	val, ok := field.(bool)
+	if !ok {
+		r.fail(key, "bool", field, nil)
+		return
+	}
+
+	assign(val)
+}
+
+// readInt converts a field to an integer and invokes the assign function
+// with that integer.
+func (r *metadataReader) readInt(key string, assign func(val int)) {
+	field, ok := r.metadata[key]
+	if !ok || field == nil {
+		return
+	}
+
+	switch val := field.(type) {
+	case int:
+		assign(val)
+	case int64:
+		assign(int(val))
+	case uint64:
+		assign(int(val))
+	case float64:
+		// JSON front matter doesn't distinguish between integers and floats.
+		if val != float64(int(val)) {
+			r.fail(key, "integer", field, nil)
+			return
+		}
+		assign(int(val))
+	default:
+		r.fail(key, "integer", field, nil)
+	}
 }
 
-// readPrimitive converts a field to a date and invokes the assignFn
+// readDate converts a field to a date and invokes the assign function
 // with that date.
-func readDate(field interface{}, assign assignFn) {
-	if field == nil {
+func (r *metadataReader) readDate(key string, assign func(val time.Time)) {
+	field, ok := r.metadata[key]
+	if !ok || field == nil {
+		return
+	}
+
+	str, ok := field.(string)
+	if !ok {
+		r.fail(key, "date", field, nil)
 		return
 	}
 
-	date, err := time.Parse(dateFormat, field.(string))
+	date, err := parseDate(str)
 	if err != nil {
-		panic(err)
+		r.fail(key, "date in the form YYYY-MM-DD", field, err)
+		return
 	}
 
 	assign(date)
 }
 
-// readList converts a field to a list and invokes the assignFn for
-// each item in that list.
-func readList(field interface{}, assign assignFn) {
-	if field == nil {
+// readList converts a field to a list of strings and invokes the assign
+// function for each item in that list.
+func (r *metadataReader) readList(key string, assign func(val string)) {
+	field, ok := r.metadata[key]
+	if !ok || field == nil {
 		return
 	}
 
-	list, _ := field.([]interface{})
+	list, ok := field.([]interface{})
+	if !ok {
+		r.fail(key, "list", field, nil)
+		return
+	}
 
-	for i := 0; i < len(list); i++ {
-		assign(list[i])
+	for i, item := range list {
+		val, ok := item.(string)
+		if !ok {
+			r.fail(fmt.Sprintf("%s[%d]", key, i), "string", item, nil)
+			continue
+		}
+		assign(val)
 	}
 }
 
-// readMap converts a field to a map of strings mapped against interface{}
+// readMap converts a field to a map of strings mapped against strings
 // and invokes the assign function for each key-value pair in that map.
-func readMap(field interface{}, assign func(key, val interface{})) {
-	if field == nil {
+func (r *metadataReader) readMap(key string, assign func(key, val string)) {
+	field, ok := r.metadata[key]
+	if !ok || field == nil {
 		return
 	}
 
-	mapp, _ := field.(map[interface{}]interface{})
+	mapp, ok := normalize(field).(map[string]interface{})
+	if !ok {
+		r.fail(key, "map", field, nil)
+		return
+	}
+
+	for mapKey, v := range mapp {
+		val, ok := v.(string)
+		if !ok {
+			r.fail(key+"."+mapKey, "string", v, nil)
+			continue
+		}
+		assign(mapKey, val)
+	}
+}
+
+// normalize converts all maps inside a decoded front matter value to
+// maps with string keys, so that the value can be encoded as JSON. It
+// also converts dates to strings.
+func normalize(val interface{}) interface{} {
+	switch v := val.(type) {
+	case metadata:
+		return normalize(map[string]interface{}(v))
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = normalize(item)
+		}
+		return m
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = normalize(item)
+		}
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = normalize(item)
+		}
+		return list
+	default:
+		return normalizeDate(v)
+	}
+}
+
+// parseDate parses a date in the form YYYY-MM-DD. Dates including a
+// time, like TOML date-times, are accepted in RFC 3339 format.
+func parseDate(str string) (time.Time, error) {
+	date, err := time.Parse(dateFormat, str)
+	if err == nil {
+		return date, nil
+	}
+	if dateTime, rfcErr := time.Parse(time.RFC3339, str); rfcErr == nil {
+		return dateTime, nil
+	}
+	return date, err
+}
+
+// fail records an invalid value for the given key.
+func (r *metadataReader) fail(key, expected string, actual interface{}, err error) {
+	r.errs = append(r.errs, &MetadataError{
+		Key:      key,
+		Expected: expected,
+		Actual:   typeName(actual),
+		Err:      err,
+	})
+}
 
-	for k, v := range mapp {
-		assign(k, v)
+// typeName returns a human-readable name for the type of a value that
+// has been decoded from the front matter.
+func typeName(val interface{}) string {
+	switch val.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case int, int64, uint64:
+		return "integer"
+	case float64:
+		return "float"
+	case []interface{}:
+		return "list"
+	case map[interface{}]interface{}, map[string]interface{}:
+		return "map"
+	default:
+		return fmt.Sprintf("%T", val)
 	}
 }
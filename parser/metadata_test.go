@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/verless/verless/test"
+)
+
+// TestMarkdown_ParsePage_invalidMetadata checks if invalid front matter
+// values are reported with the key, the expected and the actual type.
+func TestMarkdown_ParsePage_invalidMetadata(t *testing.T) {
+	parser := NewMarkdown()
+
+	tests := map[string]struct {
+		src      string
+		expected MetadataErrors
+	}{
+		"valid metadata": {
+			src: `---
+Title: Coffee Roasting Basics
+Date: 2020-03-30
+Hidden: true
+---`,
+		},
+		"invalid types": {
+			src: `---
+Title: 42
+Hidden: "yes"
+Tags:
+    - Coffee
+    - true
+---`,
+			expected: MetadataErrors{
+				{Key: "Title", Expected: "string", Actual: "integer"},
+				{Key: "Tags[1]", Expected: "string", Actual: "bool"},
+				{Key: "Hidden", Expected: "bool", Actual: "string"},
+			},
+		},
+		"invalid weight": {
+			src: `---
+Weight: 1.5
+---`,
+			expected: MetadataErrors{
+				{Key: "Weight", Expected: "integer", Actual: "float"},
+			},
+		},
+		"invalid date": {
+			src: `---
+Date: 30.03.2020
+---`,
+			expected: MetadataErrors{
+				{Key: "Date", Expected: "date in the form YYYY-MM-DD", Actual: "string"},
+			},
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		_, err := parser.ParsePage([]byte(testCase.src))
+		if testCase.expected == nil {
+			test.Ok(t, err)
+			continue
+		}
+
+		var metadataErrs MetadataErrors
+		test.Assert(t, errors.As(err, &metadataErrs), "expected MetadataErrors, got %v", err)
+		test.Equals(t, len(testCase.expected), len(metadataErrs))
+
+		for i, expected := range testCase.expected {
+			test.Equals(t, expected.Key, metadataErrs[i].Key)
+			test.Equals(t, expected.Expected, metadataErrs[i].Expected)
+			test.Equals(t, expected.Actual, metadataErrs[i].Actual)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/verless/verless/model"
+)
+
+const (
+	// Field types that can be declared for front matter fields.
+	stringField = "string"
+	numberField = "number"
+	boolField   = "bool"
+	dateField   = "date"
+	listField   = "list"
+	enumField   = "enum"
+)
+
+// ApplySchema validates the given front matter fields against the field
+// schema of a page type. It returns a copy of the fields where all missing
+// fields are set to their default values.
+//
+// Because field names in the configuration are case-insensitive, fields
+// are matched regardless of their case. If any field is missing or has an
+// invalid value, ApplySchema returns a MetadataErrors instance.
+func ApplySchema(t *model.Type, fields map[string]interface{}) (map[string]interface{}, error) {
+	applied := make(map[string]interface{}, len(fields))
+	for key, val := range fields {
+		applied[key] = val
+	}
+
+	if t == nil || len(t.Fields) == 0 {
+		return applied, nil
+	}
+
+	// Check the fields in a stable order so that the errors are stable.
+	names := make([]string, 0, len(t.Fields))
+	for name := range t.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs MetadataErrors
+
+	for _, name := range names {
+		field := t.Fields[name]
+		key, val, exists := lookupField(fields, name)
+
+		if !exists {
+			switch {
+			case field.Default != nil:
+				applied[name] = field.Default
+			case field.Required:
+				errs = append(errs, &MetadataError{Key: name, Expected: expectedType(field)})
+			}
+			continue
+		}
+
+		if err := checkField(field, val); err != nil {
+			err.Key = key
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return applied, errs
+	}
+
+	return applied, nil
+}
+
+// lookupField finds the field with the given name regardless of its case
+// and returns the actual key along with the value.
+func lookupField(fields map[string]interface{}, name string) (string, interface{}, bool) {
+	for key, val := range fields {
+		if strings.EqualFold(key, name) && val != nil {
+			return key, val, true
+		}
+	}
+	return "", nil, false
+}
+
+// checkField checks if a value matches the declared field type. If it
+// doesn't, checkField returns a MetadataError without key.
+func checkField(field model.Field, val interface{}) *MetadataError {
+	var (
+		valid bool
+		err   error
+	)
+
+	switch strings.ToLower(field.Type) {
+	case "":
+		valid = true
+	case stringField:
+		_, valid = val.(string)
+	case numberField:
+		switch val.(type) {
+		case int, int64, uint64, float64:
+			valid = true
+		}
+	case boolField:
+		_, valid = val.(bool)
+	case dateField:
+		var str string
+		if str, valid = val.(string); valid {
+			_, err = parseDate(str)
+			valid = err == nil
+		}
+	case listField:
+		_, valid = val.([]interface{})
+	case enumField:
+		str, _ := val.(string)
+		for _, v := range field.Values {
+			if str == v {
+				valid = true
+			}
+		}
+	default:
+		err = fmt.Errorf("unknown field type %s", field.Type)
+	}
+
+	if valid {
+		return nil
+	}
+
+	return &MetadataError{
+		Expected: expectedType(field),
+		Actual:   actualValue(field, val),
+		Err:      err,
+	}
+}
+
+// expectedType returns a human-readable description of the field type.
+func expectedType(field model.Field) string {
+	switch strings.ToLower(field.Type) {
+	case dateField:
+		return "date in the form YYYY-MM-DD"
+	case enumField:
+		return fmt.Sprintf("one of %s", strings.Join(field.Values, ", "))
+	case "":
+		return "any value"
+	default:
+		return field.Type
+	}
+}
+
+// actualValue describes an invalid value. For enums, the value itself is
+// more helpful than its type.
+func actualValue(field model.Field, val interface{}) string {
+	if str, ok := val.(string); ok && strings.ToLower(field.Type) == enumField {
+		return fmt.Sprintf("%q", str)
+	}
+	return typeName(val)
+}
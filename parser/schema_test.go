@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/verless/verless/model"
+	"github.com/verless/verless/test"
+)
+
+// TestApplySchema checks if front matter fields are validated against a
+// page type and if missing fields are set to their defaults.
+func TestApplySchema(t *testing.T) {
+	recipe := &model.Type{
+		Fields: map[string]model.Field{
+			"servings":   {Type: "number", Required: true},
+			"difficulty": {Type: "enum", Values: []string{"easy", "hard"}, Default: "easy"},
+			"cooked":     {Type: "date"},
+			"tags":       {Type: "list"},
+		},
+	}
+
+	tests := map[string]struct {
+		fields   map[string]interface{}
+		expected map[string]interface{}
+		errs     MetadataErrors
+	}{
+		"valid fields with default": {
+			fields: map[string]interface{}{"Servings": 4, "Cooked": "2021-01-15"},
+			expected: map[string]interface{}{
+				"Servings":   4,
+				"Cooked":     "2021-01-15",
+				"difficulty": "easy",
+			},
+		},
+		"missing required field": {
+			fields: map[string]interface{}{"Difficulty": "hard"},
+			errs: MetadataErrors{
+				{Key: "servings", Expected: "number"},
+			},
+		},
+		"invalid values": {
+			fields: map[string]interface{}{
+				"Servings":   "four",
+				"Difficulty": "medium",
+				"Cooked":     "15.01.2021",
+				"Tags":       "soup",
+			},
+			errs: MetadataErrors{
+				{Key: "Cooked", Expected: "date in the form YYYY-MM-DD", Actual: "string"},
+				{Key: "Difficulty", Expected: "one of easy, hard", Actual: `"medium"`},
+				{Key: "Servings", Expected: "number", Actual: "string"},
+				{Key: "Tags", Expected: "list", Actual: "string"},
+			},
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		fields, err := ApplySchema(recipe, testCase.fields)
+
+		if testCase.errs == nil {
+			test.Ok(t, err)
+			test.Equals(t, testCase.expected, fields)
+			continue
+		}
+
+		var metadataErrs MetadataErrors
+		test.Assert(t, errors.As(err, &metadataErrs), "expected MetadataErrors, got %v", err)
+		test.Equals(t, len(testCase.errs), len(metadataErrs))
+
+		for i, expected := range testCase.errs {
+			test.Equals(t, expected.Key, metadataErrs[i].Key)
+			test.Equals(t, expected.Expected, metadataErrs[i].Expected)
+			test.Equals(t, expected.Actual, metadataErrs[i].Actual)
+		}
+	}
+}
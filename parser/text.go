@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"bytes"
+	gohtml "html"
+	"regexp"
+	"strings"
+
+	"github.com/verless/verless/model"
+)
+
+var (
+	// paragraphSeparator matches blank lines separating paragraphs.
+	paragraphSeparator = regexp.MustCompile(`\n\s*\n`)
+)
+
+// NewText initializes and returns a new plain text parser.
+func NewText() *text {
+	return &text{}
+}
+
+// text is an internal type that satisfies the build.Parser interface.
+// It reads the front matter and converts the remaining plain text into
+// HTML paragraphs.
+type text struct{}
+
+// ParsePage converts the byte contents of a text file to an instance
+// of model.Page. The text is escaped, and each block of lines separated
+// by a blank line becomes a paragraph.
+func (t *text) ParsePage(src []byte) (model.Page, error) {
+	metadata, content, err := decodeAnyFrontMatter(src)
+	if err != nil {
+		return model.Page{}, err
+	}
+
+	var buf bytes.Buffer
+
+	normalized := strings.ReplaceAll(string(content), "\r\n", "\n")
+
+	for _, paragraph := range paragraphSeparator.Split(normalized, -1) {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		buf.WriteString("<p>")
+		buf.WriteString(strings.ReplaceAll(gohtml.EscapeString(paragraph), "\n", "<br>\n"))
+		buf.WriteString("</p>\n")
+	}
+
+	return newPage(metadata, buf.String())
+}
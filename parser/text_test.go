@@ -0,0 +1,26 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/verless/verless/test"
+)
+
+// TestText_ParsePage checks if plain text is escaped and converted to
+// HTML paragraphs.
+func TestText_ParsePage(t *testing.T) {
+	parser := NewText()
+
+	page, err := parser.ParsePage([]byte(`+++
+Title = "Coffee Roasting Basics"
++++
+Roasting coffee <at home>
+is easy.
+
+Let's start.
+`))
+	test.Ok(t, err)
+
+	test.Equals(t, "Coffee Roasting Basics", page.Title)
+	test.Equals(t, "<p>Roasting coffee &lt;at home&gt;<br>\nis easy.</p>\n<p>Let&#39;s start.</p>\n", string(page.Content))
+}
@@ -1,135 +1,300 @@
-// Package atom provides and implements the atom plugin.
+// Package atom provides and implements the atom plugin. Besides the
+// Atom feed, the plugin also generates RSS 2.0 and JSON feeds.
 package atom
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
-	"github.com/gorilla/feeds"
 	"github.com/spf13/afero"
+	"github.com/verless/verless/config"
 	"github.com/verless/verless/model"
+	"github.com/verless/verless/taxonomy"
+	"github.com/verless/verless/tree"
 )
 
 const (
-	// filename is the filename for the RSS feed.
-	filename string = "atom.xml"
+	// updatedKey is the front matter key for the date a page has been
+	// updated the last time.
+	updatedKey string = "Updated"
+	// dateFormat is the date format expected for updatedKey.
+	dateFormat string = "2006-01-02"
 )
 
-// New creates a new atom plugin that generated a RSS feed with the
-// provided metadata and stores the XML file in outputDir.
-func New(meta *model.Meta, fs afero.Fs, outputDir string) *atom {
+// New creates a new atom plugin that generates feeds with the metadata of
+// each site and stores the feed files in outputDir.
+func New(cfg *config.Config, fs afero.Fs, outputDir string) *atom {
 	a := atom{
-		meta: meta,
-		feed: &feeds.Feed{
-			Title:       meta.Title,
-			Link:        &feeds.Link{Href: meta.Base},
-			Description: meta.Description,
-			Author:      &feeds.Author{Name: meta.Author},
-			Updated:     time.Time{},
-			Created:     time.Now(),
-			Subtitle:    meta.Subtitle,
-		},
+		cfg:       cfg.Feeds,
 		fs:        fs,
 		outputDir: outputDir,
+		feeds:     make([]*feed, 0),
+		now:       time.Now().UTC(),
 	}
 
-	a.feedItems = make(chan *feeds.Item)
+	if len(a.cfg.Formats) == 0 {
+		a.cfg.Formats = []string{formatAtom}
+	}
+
+	for _, name := range a.cfg.Taxonomies {
+		a.taxonomyBases = append(a.taxonomyBases, taxonomy.Base(name, cfg.Taxonomies[name]))
+	}
 
 	return &a
 }
 
-// atom is the actual atom plugin. It stores all RSS feed items
-// as a feeds.Feed and renders those items in a XML file.s
+// atom is the actual atom plugin. It collects the pages of the entire
+// site, of each section and of each taxonomy term and writes them into
+// feeds in all configured formats. On multilingual websites, each
+// language gets its own feeds.
 type atom struct {
-	meta      *model.Meta
-	feed      *feeds.Feed
-	feedItems chan *feeds.Item
-	fs        afero.Fs
-	outputDir string
-
-	// workerShouldStop is a channel which indicates that the worker should stop working.
-	// To stop it, pass true to it. The worker will close the channel.
-	workerShouldStop chan bool
-	// workerFinishedSignal gets closed by the worker to indicate that it finished all it's work.
-	workerFinishedSignal chan bool
+	cfg           config.Feeds
+	fs            afero.Fs
+	outputDir     string
+	taxonomyBases []string
+	feeds         []*feed
+	// now is the build time, which is used as the date of feeds without
+	// any dated pages.
+	now time.Time
+}
+
+// feed is a format-independent feed for a single route.
+type feed struct {
+	Meta    *model.Meta
+	Route   string
+	Title   string
+	Link    string
+	Updated time.Time
+	Items   []item
+}
+
+// item is a format-independent feed item.
+type item struct {
+	ID          string
+	Title       string
+	Link        string
+	Description string
+	Content     string
+	Author      string
+	Categories  []string
+	Published   time.Time
+	Updated     time.Time
+}
+
+// ProcessPage isn't needed by the atom plugin.
+func (a *atom) ProcessPage(_ *model.Page) error {
+	return nil
+}
+
+// PreWrite creates the site-wide feed and, if configured, the feeds for
+// all sections and taxonomy terms. Those feeds contain all visible pages
+// of the respective list page.
+//
+// The feeds are stored in the directories of the list pages, so the site
+// of each language gets its own feeds.
+func (a *atom) PreWrite(site *model.Site) error {
+	for _, format := range a.cfg.Formats {
+		if _, ok := encoders[format]; !ok {
+			return fmt.Errorf("invalid feed format %s: expected one of %s", format, strings.Join(formats(), ", "))
+		}
+	}
+
+	err := tree.Walk(site.Root, func(route string, node tree.Node) error {
+		n := node.(*model.Node)
+
+		if route != tree.RootPath && !a.isSection(route, n) && !a.isTerm(route) {
+			return nil
+		}
+
+		f, err := a.newFeed(&site.Meta, route, &n.ListPage)
+		if err != nil {
+			return err
+		}
+
+		a.feeds = append(a.feeds, f)
+		return nil
+	}, -1)
+
+	if err != nil {
+		return err
+	}
+
+	// The site model is a tree of maps, so the feeds are sorted to
+	// write them in the same order for each build.
+	sort.Slice(a.feeds, func(i, j int) bool {
+		return a.feeds[i].Route < a.feeds[j].Route
+	})
+
+	return nil
 }
 
-// PreProcessPages starts a worker goroutine which handles the a.feed.Add.
-// This improves speed as the ProcessPage can add new items in a non blocking way.
-func (a *atom) PreProcessPages() error {
-	a.workerShouldStop = make(chan bool)
-	a.workerFinishedSignal = make(chan bool)
-
-	go func() {
-		defer func() {
-			close(a.workerShouldStop)
-			close(a.workerFinishedSignal)
-		}()
-
-		for {
-			select {
-			case shouldStop := <-a.workerShouldStop:
-				if shouldStop {
-					return
-				}
-			default:
+// PostWrite writes all feeds in all configured formats into the
+// directories of their respective list pages.
+func (a *atom) PostWrite() error {
+	for _, f := range a.feeds {
+		dir := filepath.Join(a.outputDir, f.Route)
+
+		if err := a.fs.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+
+		for _, format := range a.cfg.Formats {
+			enc := encoders[format]
+
+			data, err := enc.encode(f, url(f.Meta, path.Join(f.Route, enc.filename)))
+			if err != nil {
+				return err
 			}
 
-			select {
-			case feedItem := <-a.feedItems:
-				a.feed.Add(feedItem)
-			default:
+			if err := afero.WriteFile(a.fs, filepath.Join(dir, enc.filename), data, 0644); err != nil {
+				return err
 			}
 		}
-	}()
+	}
 
 	return nil
 }
 
-// ProcessPage takes a page to be processed by the plugin, reads
-// metadata for that page and creates a new feed item from it.
-func (a *atom) ProcessPage(page *model.Page) error {
-	if page.Hidden || page.IsCustomListPage() {
-		return nil
+// isSection determines whether the node behind the given route is a
+// content section that should get its own feed.
+func (a *atom) isSection(route string, node *model.Node) bool {
+	return a.cfg.Sections && (hasPages(node) || node.ListPage.IsCustomListPage())
+}
+
+// hasPages determines whether the node or one of its descendants contains
+// pages, like a section whose pages are all stored in subdirectories.
+// Nodes generated for taxonomies never contain pages on their own.
+func hasPages(node *model.Node) bool {
+	if len(node.Pages) > 0 {
+		return true
+	}
+	for _, child := range node.Children() {
+		if hasPages(child.(*model.Node)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTerm determines whether the given route is the route of a term of
+// one of the configured taxonomies.
+func (a *atom) isTerm(route string) bool {
+	for _, base := range a.taxonomyBases {
+		if path.Dir(route) == base {
+			return true
+		}
+	}
+	return false
+}
+
+// newFeed creates a feed for the list page behind the given route. The
+// route is relative to the site's language, while the feed is stored in
+// the list page's route. The newest pages come first, regardless of the
+// order of the list page.
+func (a *atom) newFeed(meta *model.Meta, route string, listPage *model.ListPage) (*feed, error) {
+	f := feed{
+		Meta:  meta,
+		Route: listPage.Route,
+		Title: meta.Title,
+		Link:  url(meta, listPage.Route),
+		Items: make([]item, 0, len(listPage.Pages)),
+	}
+
+	if route != tree.RootPath {
+		title := listPage.Title
+		if title == "" {
+			title = path.Base(route)
+		}
+		f.Title = fmt.Sprintf("%s: %s", meta.Title, title)
+	}
+
+	pages := make([]*model.Page, len(listPage.Pages))
+	copy(pages, listPage.Pages)
+
+	if err := model.SortPages(pages, model.SortByDate, model.SortDesc); err != nil {
+		return nil, err
+	}
+
+	for _, page := range pages {
+		i, err := a.newItem(meta, page)
+		if err != nil {
+			return nil, err
+		}
+
+		if i.Updated.After(f.Updated) {
+			f.Updated = i.Updated
+		}
+
+		f.Items = append(f.Items, i)
+	}
+
+	// Atom feeds require a date, so a feed without any dated pages is
+	// considered as updated by the current build.
+	if f.Updated.IsZero() {
+		f.Updated = a.now
 	}
 
-	canonical := fmt.Sprintf("%s%s", a.meta.Base, page.Href)
+	return &f, nil
+}
+
+// newItem creates a feed item for the given page.
+func (a *atom) newItem(meta *model.Meta, page *model.Page) (item, error) {
+	link := url(meta, page.Href)
 
-	item := &feeds.Item{
+	i := item{
+		ID:          link,
 		Title:       page.Title,
-		Link:        &feeds.Link{Href: canonical},
+		Link:        link,
 		Description: page.Description,
-		Id:          canonical,
-		Created:     page.Date,
+		Author:      page.Author,
+		Published:   page.Date,
+		Updated:     page.Date,
 	}
 
-	a.feedItems <- item
-	return nil
-}
+	if i.Author == "" {
+		i.Author = meta.Author
+	}
 
-// PostProcessPages stops and waits for the worker from PreProcessPages to finish.
-func (a *atom) PostProcessPages() error {
-	if a.workerShouldStop != nil {
-		a.workerShouldStop <- true
+	if a.cfg.Content {
+		i.Content = string(page.Content)
 	}
-	_, _ = <-a.workerFinishedSignal
-	return nil
+
+	for _, tag := range page.Tags {
+		i.Categories = append(i.Categories, tag.Name)
+	}
+
+	if val := page.Param(updatedKey); val != nil {
+		updated, err := parseDate(val)
+		if err != nil {
+			if page.SourcePath() != "" {
+				return i, fmt.Errorf("%s: %w", page.SourcePath(), err)
+			}
+			return i, err
+		}
+		i.Updated = updated
+	}
+
+	return i, nil
 }
 
-// PreWrite isn't needed by the atom plugin.
-func (a *atom) PreWrite(_ *model.Site) error {
-	return nil
+// url returns the absolute URL for the given href.
+func url(meta *model.Meta, href string) string {
+	return strings.TrimSuffix(meta.Base, "/") + href
 }
 
-// PostWrite writes the internal feed.Feed instance into a file
-// directly in the output directory.
-func (a *atom) PostWrite() error {
-	path := filepath.Join(a.outputDir, filename)
-	atomFile, err := a.fs.Create(path)
-	if err != nil {
-		return err
+// parseDate parses the value of the updatedKey front matter field.
+func parseDate(val interface{}) (time.Time, error) {
+	if str, ok := val.(string); ok {
+		if date, err := time.Parse(dateFormat, str); err == nil {
+			return date, nil
+		}
+		if date, err := time.Parse(time.RFC3339, str); err == nil {
+			return date, nil
+		}
 	}
-
-	return a.feed.WriteAtom(atomFile)
+	return time.Time{}, fmt.Errorf("invalid value for key %s: expected date in the form YYYY-MM-DD, got %v", updatedKey, val)
 }
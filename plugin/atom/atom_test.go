@@ -1,71 +1,344 @@
 package atom
 
 import (
-	"fmt"
-	"path"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/afero"
+	"github.com/verless/verless/config"
 	"github.com/verless/verless/model"
 	"github.com/verless/verless/test"
+	"github.com/verless/verless/tree"
 )
 
-var (
-	// testPages is a set of pages used for testing.
-	testPages = []model.Page{
-		{ID: "page-0", Route: "/route-0", Title: "Page 1", Href: "/route-0/page-0"},
-		{ID: "page-1", Route: "/route-1/route-22/route-333", Title: "Page 2", Href: "/route-1/route-22/route-333/page-1"},
-		{ID: "page-2", Route: "/route-2", Title: "Page 3", Href: "/route-2/page-2"},
-		{ID: "page-3", Route: "/route-3", Title: "Page 4", Href: "/route-3/page-3"},
-		{ID: "page-4", Route: "/", Title: "Page 5", Href: "/page-4"},
+// testSite creates a site model with a blog section and a tag.
+func testSite(t *testing.T) *model.Site {
+	espresso := model.Page{
+		Href:        "/blog/espresso",
+		Title:       "Espresso",
+		Description: "Making espresso",
+		Content:     "<p>Espresso</p>",
+		Date:        time.Date(2020, 8, 14, 0, 0, 0, 0, time.UTC),
+		Tags:        []model.Tag{{Name: "Coffee", Href: "/tags/coffee"}},
+		Params:      map[string]interface{}{"Updated": "2020-09-01"},
 	}
-)
+	cappuccino := model.Page{
+		Href:   "/blog/cappuccino",
+		Title:  "Cappuccino",
+		Author: "Jane",
+		Date:   time.Date(2020, 8, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	site := model.NewSite()
+	site.Meta = model.Meta{Title: "Coffee Blog", Author: "John", Base: "https://example.com"}
+
+	blog := model.NewNode()
+	blog.Pages = []model.Page{espresso, cappuccino}
+	blog.ListPage.Route = "/blog"
+	blog.ListPage.Title = "Blog"
+	blog.ListPage.Pages = []*model.Page{&blog.Pages[0], &blog.Pages[1]}
+
+	tags := model.NewNode()
+	tags.ListPage.Route = "/tags"
+
+	coffee := model.NewNode()
+	coffee.ListPage.Route = "/tags/coffee"
+	coffee.ListPage.Title = "Coffee"
+	coffee.ListPage.Pages = []*model.Page{&blog.Pages[0]}
+
+	site.Root.ListPage.Route = "/"
+	site.Root.ListPage.Pages = blog.ListPage.Pages
+
+	test.Ok(t, tree.CreateNode("/blog", site.Root, blog))
+	test.Ok(t, tree.CreateNode("/tags", site.Root, tags))
+	test.Ok(t, tree.CreateNode("/tags/coffee", site.Root, coffee))
+
+	return &site
+}
 
-// TestAtom_ProcessPage checks if the atom plugin creates a new
-// RSS feed item for each processed page.
-func TestAtom_ProcessPage(t *testing.T) {
+// TestAtom_PreWrite checks if the atom plugin creates the configured
+// feeds along with their items.
+func TestAtom_PreWrite(t *testing.T) {
 	tests := map[string]struct {
-		pages         []model.Page
-		expectedError error
+		feeds          config.Feeds
+		expectedRoutes []string
+		expectedError  bool
 	}{
-		"normal pages": {
-			pages: testPages,
+		"site-wide feed": {
+			expectedRoutes: []string{"/"},
+		},
+		"section feeds": {
+			feeds:          config.Feeds{Sections: true},
+			expectedRoutes: []string{"/", "/blog"},
+		},
+		"tag feeds": {
+			feeds:          config.Feeds{Sections: true, Taxonomies: []string{"tags"}},
+			expectedRoutes: []string{"/", "/blog", "/tags/coffee"},
+		},
+		"invalid format": {
+			feeds:         config.Feeds{Formats: []string{"csv"}},
+			expectedError: true,
 		},
 	}
 
 	for name, testCase := range tests {
 		t.Log(name)
 
-		a := New(&model.Meta{
-			Base: "https://example.com",
-		}, afero.NewOsFs(), "")
+		cfg := config.Config{Feeds: testCase.feeds}
+		a := New(&cfg, afero.NewMemMapFs(), "/out")
 
-		// Note that hte ProcessPage functionality is heavily coupled with the Pre/Post methods.
-		// That's why we have to call it here also.
-		err := a.PreProcessPages()
-		if test.ExpectedError(t, nil, err) != test.IsCorrectNil {
-			return
+		err := a.PreWrite(testSite(t))
+		if testCase.expectedError {
+			test.Assert(t, err != nil, "invalid formats should return an error")
+			continue
 		}
+		test.Ok(t, err)
 
-		for i, page := range testCase.pages {
-			t.Logf("process page number %v, route '%v'", i, page.Route)
-			err := a.ProcessPage(&page)
-			if test.ExpectedError(t, testCase.expectedError, err) != test.IsCorrectNil {
-				return
-			}
+		routes := make([]string, 0)
+		for _, f := range a.feeds {
+			routes = append(routes, f.Route)
+		}
+		test.Equals(t, testCase.expectedRoutes, routes)
+
+		// The newest page has to come first.
+		root := a.feeds[0]
+		test.Equals(t, 2, len(root.Items))
+		test.Equals(t, "https://example.com/blog/cappuccino", root.Items[0].Link)
+		test.Equals(t, "Jane", root.Items[0].Author)
+		test.Equals(t, "John", root.Items[1].Author)
+		test.Equals(t, []string{"Coffee"}, root.Items[1].Categories)
+		test.Equals(t, "", root.Items[1].Content)
+		test.Equals(t, time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC), root.Items[1].Updated)
+		test.Equals(t, time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC), root.Updated)
+	}
+}
+
+// TestAtom_PostWrite checks if the atom plugin writes all feeds in all
+// configured formats.
+func TestAtom_PostWrite(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	cfg := config.Config{
+		Feeds: config.Feeds{
+			Formats:    []string{"atom", "rss", "json"},
+			Content:    true,
+			Sections:   true,
+			Taxonomies: []string{"tags"},
+		},
+	}
+	a := New(&cfg, fs, "/out")
+
+	test.Ok(t, a.PreWrite(testSite(t)))
+	test.Ok(t, a.PostWrite())
+
+	expected := map[string][]string{
+		"/out/atom.xml":              {`<link href="https://example.com/atom.xml" rel="self"></link>`, `<category term="Coffee"></category>`, `<content type="html">&lt;p&gt;Espresso&lt;/p&gt;</content>`},
+		"/out/rss.xml":               {`<rss version="2.0"`, `<category>Coffee</category>`, `<dc:creator>Jane</dc:creator>`, `<content:encoded>&lt;p&gt;Espresso&lt;/p&gt;</content:encoded>`},
+		"/out/blog/atom.xml":         {`<title>Coffee Blog: Blog</title>`},
+		"/out/tags/coffee/rss.xml":   {`<title>Coffee Blog: Coffee</title>`},
+		"/out/tags/coffee/feed.json": {`"feed_url": "https://example.com/tags/coffee/feed.json"`},
+	}
+
+	for file, contents := range expected {
+		data, err := afero.ReadFile(fs, file)
+		test.Ok(t, err)
+
+		for _, content := range contents {
+			test.Assert(t, strings.Contains(string(data), content), "%s should contain %s", file, content)
+		}
+	}
+
+	data, err := afero.ReadFile(fs, "/out/feed.json")
+	test.Ok(t, err)
+
+	var jf jsonFeed
+	test.Ok(t, json.Unmarshal(data, &jf))
+
+	test.Equals(t, "https://jsonfeed.org/version/1.1", jf.Version)
+	test.Equals(t, 2, len(jf.Items))
+	test.Equals(t, "<p>Espresso</p>", jf.Items[1].ContentHTML)
+	test.Equals(t, []string{"Coffee"}, jf.Items[1].Tags)
+	test.Equals(t, "2020-09-01T00:00:00Z", jf.Items[1].DateModified)
+
+	exists, err := afero.Exists(fs, "/out/tags/atom.xml")
+	test.Ok(t, err)
+	test.Assert(t, !exists, "the taxonomy itself shouldn't have a feed")
+}
+
+// TestAtom_PreWrite_languages checks if the atom plugin creates separate
+// feeds for the site of each language.
+func TestAtom_PreWrite_languages(t *testing.T) {
+	cfg := config.Config{}
+	a := New(&cfg, afero.NewMemMapFs(), "/out")
 
-			item := a.feed.Items[i]
-			test.Equals(t, page.Title, item.Title)
+	de := model.NewSite()
+	de.Meta = model.Meta{Title: "Kaffeeblog", Base: "https://example.com"}
+	de.Language = model.Language{Code: "de", Href: "/de"}
+	de.Root.ListPage.Route = "/de"
+	de.Root.Pages = []model.Page{{Href: "/de/espresso", Title: "Espresso"}}
+	de.Root.ListPage.Pages = []*model.Page{&de.Root.Pages[0]}
 
-			canonicalLink := fmt.Sprintf("%s%s", a.meta.Base, path.Join(page.Route, page.ID))
-			test.Equals(t, canonicalLink, item.Link.Href)
+	test.Ok(t, a.PreWrite(testSite(t)))
+	test.Ok(t, a.PreWrite(&de))
+
+	test.Equals(t, 2, len(a.feeds))
+	test.Equals(t, "/", a.feeds[0].Route)
+	test.Equals(t, "/de", a.feeds[1].Route)
+	test.Equals(t, "Kaffeeblog", a.feeds[1].Title)
+	test.Equals(t, "https://example.com/de/espresso", a.feeds[1].Items[0].Link)
+}
+
+// TestAtom_PostWrite_valid checks if the feeds in all formats contain all
+// elements required by their specifications, even for sections without
+// direct pages and for pages without a date.
+func TestAtom_PostWrite_valid(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	cfg := config.Config{
+		Feeds: config.Feeds{
+			Formats:  []string{formatAtom, formatRSS, formatJSON},
+			Sections: true,
+		},
+	}
+	a := New(&cfg, fs, "/out")
+
+	// The docs section only contains an undated page in a subdirectory.
+	site := model.NewSite()
+	site.Meta = model.Meta{Title: "Coffee Docs", Base: "https://example.com"}
+
+	guides := model.NewNode()
+	guides.ListPage.Route = "/docs/guides"
+	guides.Pages = []model.Page{{Href: "/docs/guides/grinding", Title: "Grinding"}}
+	guides.ListPage.Pages = []*model.Page{&guides.Pages[0]}
+
+	site.Root.ListPage.Route = "/"
+	site.Root.ListPage.Pages = guides.ListPage.Pages
+
+	test.Ok(t, tree.CreateNode("/docs/guides", site.Root, guides))
+	docs, err := tree.ResolveNode("/docs", site.Root)
+	test.Ok(t, err)
+	docs.(*model.Node).ListPage.Route = "/docs"
+	docs.(*model.Node).ListPage.Pages = guides.ListPage.Pages
+
+	test.Ok(t, a.PreWrite(&site))
+	test.Ok(t, a.PostWrite())
+
+	validators := map[string]func(t *testing.T, data []byte){
+		"atom.xml":  validateAtom,
+		"rss.xml":   validateRSS,
+		"feed.json": validateJSON,
+	}
+
+	for _, dir := range []string{"/out", "/out/docs", "/out/docs/guides"} {
+		for filename, validate := range validators {
+			t.Log(dir, filename)
+
+			data, err := afero.ReadFile(fs, dir+"/"+filename)
+			test.Ok(t, err)
+			validate(t, data)
 		}
+	}
+}
 
-		err = a.PostProcessPages()
-		if test.ExpectedError(t, nil, err) != test.IsCorrectNil {
-			return
+// validateAtom checks if an Atom feed contains all elements required by
+// RFC 4287.
+func validateAtom(t *testing.T, data []byte) {
+	var f struct {
+		XMLName xml.Name
+		ID      string `xml:"id"`
+		Title   string `xml:"title"`
+		Updated string `xml:"updated"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Title   string `xml:"title"`
+			Updated string `xml:"updated"`
+			Link    struct {
+				Href string `xml:"href,attr"`
+			} `xml:"link"`
+		} `xml:"entry"`
+	}
+	test.Ok(t, xml.Unmarshal(data, &f))
+
+	test.Equals(t, xml.Name{Space: "http://www.w3.org/2005/Atom", Local: "feed"}, f.XMLName)
+	test.Assert(t, f.ID != "" && f.Title != "", "the feed requires an id and a title")
+	_, err := time.Parse(time.RFC3339, f.Updated)
+	test.Ok(t, err)
+
+	test.Assert(t, len(f.Entries) > 0, "the feed should contain entries")
+	for _, e := range f.Entries {
+		test.Assert(t, e.ID != "" && e.Title != "" && e.Link.Href != "", "each entry requires an id, a title and a link")
+		_, err := time.Parse(time.RFC3339, e.Updated)
+		test.Ok(t, err)
+	}
+}
+
+// validateRSS checks if an RSS feed contains all elements required by
+// the RSS 2.0 specification.
+func validateRSS(t *testing.T, data []byte) {
+	var f struct {
+		XMLName xml.Name
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title string `xml:"title"`
+			// Links contains both the channel link and the self link in
+			// the Atom namespace.
+			Links         []string `xml:"link"`
+			Description   *string  `xml:"description"`
+			LastBuildDate string   `xml:"lastBuildDate"`
+			Items         []struct {
+				Title   string `xml:"title"`
+				GUID    string `xml:"guid"`
+				PubDate string `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	test.Ok(t, xml.Unmarshal(data, &f))
+
+	test.Equals(t, "rss", f.XMLName.Local)
+	test.Equals(t, "2.0", f.Version)
+	test.Assert(t, f.Channel.Title != "" && len(f.Channel.Links) > 0 && f.Channel.Links[0] != "", "the channel requires a title and a link")
+	test.Assert(t, f.Channel.Description != nil, "the channel requires a description")
+	_, err := time.Parse(time.RFC1123Z, f.Channel.LastBuildDate)
+	test.Ok(t, err)
+
+	test.Assert(t, len(f.Channel.Items) > 0, "the channel should contain items")
+	for _, i := range f.Channel.Items {
+		test.Assert(t, i.Title != "" && i.GUID != "", "each item requires a title and a guid")
+		if i.PubDate != "" {
+			_, err := time.Parse(time.RFC1123Z, i.PubDate)
+			test.Ok(t, err)
 		}
+	}
+}
+
+// validateJSON checks if a JSON feed contains all keys required by the
+// JSON Feed 1.1 specification.
+func validateJSON(t *testing.T, data []byte) {
+	var f map[string]interface{}
+	test.Ok(t, json.Unmarshal(data, &f))
+
+	test.Equals(t, "https://jsonfeed.org/version/1.1", f["version"])
+	test.Assert(t, f["title"] != "", "the feed requires a title")
+
+	items, ok := f["items"].([]interface{})
+	test.Assert(t, ok && len(items) > 0, "the feed should contain items")
 
-		test.Equals(t, len(testCase.pages), len(a.feed.Items))
+	for _, item := range items {
+		i := item.(map[string]interface{})
+		test.Assert(t, i["id"] != nil && i["id"] != "", "each item requires an id")
+
+		_, hasHTML := i["content_html"]
+		_, hasText := i["content_text"]
+		test.Assert(t, hasHTML || hasText, "each item requires content_html or content_text")
+
+		for _, key := range []string{"date_published", "date_modified"} {
+			if date, ok := i[key]; ok {
+				_, err := time.Parse(time.RFC3339, date.(string))
+				test.Ok(t, err)
+			}
+		}
 	}
 }
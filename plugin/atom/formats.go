@@ -0,0 +1,294 @@
+package atom
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"sort"
+	"time"
+)
+
+const (
+	formatAtom string = "atom"
+	formatRSS  string = "rss"
+	formatJSON string = "json"
+)
+
+// encoder converts a feed into a file of a particular format.
+type encoder struct {
+	filename string
+	encode   func(f *feed, feedURL string) ([]byte, error)
+}
+
+// encoders contains the encoders for all supported feed formats.
+var encoders = map[string]encoder{
+	formatAtom: {filename: "atom.xml", encode: encodeAtom},
+	formatRSS:  {filename: "rss.xml", encode: encodeRSS},
+	formatJSON: {filename: "feed.json", encode: encodeJSON},
+}
+
+// formats returns the names of all supported feed formats.
+func formats() []string {
+	names := make([]string, 0, len(encoders))
+	for name := range encoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type (
+	atomFeed struct {
+		XMLName  xml.Name    `xml:"feed"`
+		Xmlns    string      `xml:"xmlns,attr"`
+		Title    string      `xml:"title"`
+		ID       string      `xml:"id"`
+		Links    []atomLink  `xml:"link"`
+		Updated  string      `xml:"updated"`
+		Subtitle string      `xml:"subtitle,omitempty"`
+		Author   *atomAuthor `xml:"author,omitempty"`
+		Entries  []atomEntry `xml:"entry"`
+	}
+
+	atomLink struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr,omitempty"`
+	}
+
+	atomAuthor struct {
+		Name string `xml:"name"`
+	}
+
+	atomCategory struct {
+		Term string `xml:"term,attr"`
+	}
+
+	atomText struct {
+		Type    string `xml:"type,attr"`
+		Content string `xml:",chardata"`
+	}
+
+	atomEntry struct {
+		Title      string         `xml:"title"`
+		ID         string         `xml:"id"`
+		Link       atomLink       `xml:"link"`
+		Published  string         `xml:"published,omitempty"`
+		Updated    string         `xml:"updated"`
+		Author     *atomAuthor    `xml:"author,omitempty"`
+		Categories []atomCategory `xml:"category"`
+		Summary    *atomText      `xml:"summary,omitempty"`
+		Content    *atomText      `xml:"content,omitempty"`
+	}
+)
+
+// encodeAtom encodes a feed as Atom 1.0 feed.
+func encodeAtom(f *feed, feedURL string) ([]byte, error) {
+	af := atomFeed{
+		Xmlns:    "http://www.w3.org/2005/Atom",
+		Title:    f.Title,
+		ID:       f.Link,
+		Links:    []atomLink{{Href: f.Link}, {Href: feedURL, Rel: "self"}},
+		Updated:  formatTime(time.RFC3339, f.Updated),
+		Subtitle: f.Meta.Subtitle,
+	}
+
+	if f.Meta.Author != "" {
+		af.Author = &atomAuthor{Name: f.Meta.Author}
+	}
+
+	for _, i := range f.Items {
+		entry := atomEntry{
+			Title:     i.Title,
+			ID:        i.ID,
+			Link:      atomLink{Href: i.Link},
+			Published: formatTime(time.RFC3339, i.Published),
+			Updated:   formatTime(time.RFC3339, i.Updated),
+		}
+
+		// Each entry requires a date as well, so entries of undated
+		// pages take the date of the feed.
+		if i.Updated.IsZero() {
+			entry.Updated = formatTime(time.RFC3339, f.Updated)
+		}
+
+		if i.Author != "" {
+			entry.Author = &atomAuthor{Name: i.Author}
+		}
+		for _, category := range i.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		if i.Description != "" {
+			entry.Summary = &atomText{Type: "html", Content: i.Description}
+		}
+		if i.Content != "" {
+			entry.Content = &atomText{Type: "html", Content: i.Content}
+		}
+
+		af.Entries = append(af.Entries, entry)
+	}
+
+	return encodeXML(af)
+}
+
+type (
+	rssFeed struct {
+		XMLName   xml.Name   `xml:"rss"`
+		Version   string     `xml:"version,attr"`
+		XmlnsDC   string     `xml:"xmlns:dc,attr"`
+		XmlnsCont string     `xml:"xmlns:content,attr"`
+		XmlnsAtom string     `xml:"xmlns:atom,attr"`
+		Channel   rssChannel `xml:"channel"`
+	}
+
+	rssChannel struct {
+		Title         string    `xml:"title"`
+		Link          string    `xml:"link"`
+		SelfLink      rssLink   `xml:"atom:link"`
+		Description   string    `xml:"description"`
+		LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+		Items         []rssItem `xml:"item"`
+	}
+
+	rssLink struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+		Type string `xml:"type,attr"`
+	}
+
+	rssGUID struct {
+		IsPermaLink string `xml:"isPermaLink,attr"`
+		Value       string `xml:",chardata"`
+	}
+
+	rssItem struct {
+		Title       string   `xml:"title"`
+		Link        string   `xml:"link"`
+		GUID        rssGUID  `xml:"guid"`
+		Description string   `xml:"description,omitempty"`
+		Content     string   `xml:"content:encoded,omitempty"`
+		Creator     string   `xml:"dc:creator,omitempty"`
+		Categories  []string `xml:"category"`
+		PubDate     string   `xml:"pubDate,omitempty"`
+	}
+)
+
+// encodeRSS encodes a feed as RSS 2.0 feed.
+func encodeRSS(f *feed, feedURL string) ([]byte, error) {
+	rf := rssFeed{
+		Version:   "2.0",
+		XmlnsDC:   "http://purl.org/dc/elements/1.1/",
+		XmlnsCont: "http://purl.org/rss/1.0/modules/content/",
+		XmlnsAtom: "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			SelfLink:      rssLink{Href: feedURL, Rel: "self", Type: "application/rss+xml"},
+			Description:   f.Meta.Description,
+			LastBuildDate: formatTime(time.RFC1123Z, f.Updated),
+		},
+	}
+
+	for _, i := range f.Items {
+		rf.Channel.Items = append(rf.Channel.Items, rssItem{
+			Title:       i.Title,
+			Link:        i.Link,
+			GUID:        rssGUID{IsPermaLink: "true", Value: i.ID},
+			Description: i.Description,
+			Content:     i.Content,
+			Creator:     i.Author,
+			Categories:  i.Categories,
+			PubDate:     formatTime(time.RFC1123Z, i.Published),
+		})
+	}
+
+	return encodeXML(rf)
+}
+
+type (
+	jsonFeed struct {
+		Version     string       `json:"version"`
+		Title       string       `json:"title"`
+		HomePageURL string       `json:"home_page_url"`
+		FeedURL     string       `json:"feed_url"`
+		Description string       `json:"description,omitempty"`
+		Authors     []jsonAuthor `json:"authors,omitempty"`
+		Items       []jsonItem   `json:"items"`
+	}
+
+	jsonAuthor struct {
+		Name string `json:"name"`
+	}
+
+	jsonItem struct {
+		ID            string       `json:"id"`
+		URL           string       `json:"url"`
+		Title         string       `json:"title,omitempty"`
+		ContentHTML   string       `json:"content_html,omitempty"`
+		ContentText   *string      `json:"content_text,omitempty"`
+		Summary       string       `json:"summary,omitempty"`
+		DatePublished string       `json:"date_published,omitempty"`
+		DateModified  string       `json:"date_modified,omitempty"`
+		Authors       []jsonAuthor `json:"authors,omitempty"`
+		Tags          []string     `json:"tags,omitempty"`
+	}
+)
+
+// encodeJSON encodes a feed as JSON Feed 1.1.
+func encodeJSON(f *feed, feedURL string) ([]byte, error) {
+	jf := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		FeedURL:     feedURL,
+		Description: f.Meta.Description,
+		Items:       make([]jsonItem, 0, len(f.Items)),
+	}
+
+	if f.Meta.Author != "" {
+		jf.Authors = []jsonAuthor{{Name: f.Meta.Author}}
+	}
+
+	for _, i := range f.Items {
+		ji := jsonItem{
+			ID:            i.ID,
+			URL:           i.Link,
+			Title:         i.Title,
+			ContentHTML:   i.Content,
+			Summary:       i.Description,
+			DatePublished: formatTime(time.RFC3339, i.Published),
+			DateModified:  formatTime(time.RFC3339, i.Updated),
+			Tags:          i.Categories,
+		}
+
+		// Each item requires either an HTML or a text content, even if
+		// it is empty.
+		if ji.ContentHTML == "" {
+			description := i.Description
+			ji.ContentText = &description
+		}
+
+		if i.Author != "" {
+			ji.Authors = []jsonAuthor{{Name: i.Author}}
+		}
+
+		jf.Items = append(jf.Items, ji)
+	}
+
+	return json.MarshalIndent(jf, "", "  ")
+}
+
+func encodeXML(v interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// formatTime formats the given time or returns an empty string if the
+// time is zero.
+func formatTime(layout string, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
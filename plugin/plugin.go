@@ -6,7 +6,8 @@ import (
 	"github.com/verless/verless/model"
 	"github.com/verless/verless/plugin/atom"
 	"github.com/verless/verless/plugin/related"
-	"github.com/verless/verless/plugin/tags"
+	"github.com/verless/verless/plugin/search"
+	"github.com/verless/verless/plugin/sitemap"
 )
 
 // Plugin represents a built-in verless plugin.
@@ -14,9 +15,11 @@ type Plugin interface {
 	// ProcessPage will be invoked after parsing the page. Must be safe
 	// for concurrent usage.
 	ProcessPage(page *model.Page) error
-	// PreWrite will be invoked before writing the site.
+	// PreWrite will be invoked before writing the site. On multilingual
+	// websites, PreWrite will be invoked for the site of each language.
 	PreWrite(site *model.Site) error
-	// PostWrite will be invoked after writing the site.
+	// PostWrite will be invoked after writing the site. It is invoked
+	// only once, even for multilingual websites.
 	PostWrite() error
 }
 
@@ -31,12 +34,22 @@ type PrePostProcessPlugin interface {
 	PostProcessPages() error
 }
 
+// FuncsPlugin is a plugin that provides functions for templates.
+type FuncsPlugin interface {
+	Plugin
+
+	// Funcs returns the functions that will be available in all
+	// templates, keyed by their name.
+	Funcs() map[string]interface{}
+}
+
 // LoadAll returns a map of all available plugins. Each entry
 // is a function that returns a fully initialized plugin instance.
 func LoadAll(cfg *config.Config, fs afero.Fs, outputDir string) map[string]func() Plugin {
 	return map[string]func() Plugin{
-		"atom":    func() Plugin { return atom.New(&cfg.Site.Meta, fs, outputDir) },
+		"atom":    func() Plugin { return atom.New(cfg, fs, outputDir) },
 		"related": func() Plugin { return related.New() },
-		"tags":    func() Plugin { return tags.New() },
+		"search":  func() Plugin { return search.New(cfg, fs, outputDir) },
+		"sitemap": func() Plugin { return sitemap.New(&cfg.Site.Meta, fs, outputDir) },
 	}
 }
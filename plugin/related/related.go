@@ -22,9 +22,17 @@ func New() *related {
 
 // ProcessPage adds a given pointer to a Page instance to the plugin's page
 // map. This prevents that each page has to be resolved from the tre later.
+//
+// Related pages can be referenced by their Href as well as by their path
+// inside the content directory, which remains the same if the page has a
+// slug or a permalink pattern. On multilingual websites, pages can only
+// be related to pages in the same language.
 func (r *related) ProcessPage(page *model.Page) error {
 	r.pagesMutex.Lock()
-	r.pages[page.Href] = page
+	if page.ContentPath() != "" {
+		r.pages[key(page.Language, page.ContentPath())] = page
+	}
+	r.pages[key(page.Language, page.Href)] = page
 	r.pagesMutex.Unlock()
 	return nil
 }
@@ -42,7 +50,7 @@ func (r *related) PreWrite(site *model.Site) error {
 
 		for i, _ := range pages {
 			for _, related := range pages[i].ProvidedRelated() {
-				if p, ok := r.pages[related]; ok {
+				if p, ok := r.pages[key(pages[i].Language, related)]; ok {
 					pages[i].Related = append(pages[i].Related, p)
 				}
 			}
@@ -57,3 +65,8 @@ func (r *related) PreWrite(site *model.Site) error {
 func (r *related) PostWrite() error {
 	return nil
 }
+
+// key returns the key of a page URI in the given language.
+func key(language, uri string) string {
+	return language + ":" + uri
+}
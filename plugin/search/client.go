@@ -0,0 +1,142 @@
+package search
+
+// client is the JavaScript search client that is written next to the
+// search index. It is kept as a string constant so that verless remains
+// a single binary without any additional files.
+//
+// Themes can use the client as follows:
+//
+//	<script src="/search/search.js"></script>
+//	<input id="search" type="search">
+//	<ul id="results"></ul>
+//	<script>verlessSearch.bind("#search", "#results");</script>
+//
+// The client provides verlessSearch.search(query, section) as well,
+// which returns a promise for the list of matching pages.
+const client = `(function (global) {
+    "use strict";
+
+    var script = document.currentScript;
+    var base = script ? script.src.replace(/[^\/]*$/, "") : "/search/";
+    var requests = {};
+
+    function fetchJSON(url) {
+        if (!requests[url]) {
+            requests[url] = fetch(url).then(function (res) {
+                if (!res.ok) {
+                    throw new Error("failed to load search index " + url + ": " + res.status);
+                }
+                return res.json();
+            });
+        }
+        return requests[url];
+    }
+
+    // load resolves the pages of the given section, or of all sections
+    // if no section is given.
+    function load(section) {
+        return fetchJSON(base + "index.json").then(function (index) {
+            if (index.pages) {
+                return index.pages;
+            }
+            var sections = Object.keys(index.shards).filter(function (s) {
+                return section === undefined || s === section;
+            });
+            return Promise.all(sections.map(function (s) {
+                return fetchJSON(base + index.shards[s]);
+            })).then(function (shards) {
+                return shards.reduce(function (pages, shard) {
+                    return pages.concat(shard.pages);
+                }, []);
+            });
+        });
+    }
+
+    function count(text, term) {
+        var n = 0, i = text.indexOf(term);
+        while (i !== -1) {
+            n++;
+            i = text.indexOf(term, i + term.length);
+        }
+        return n;
+    }
+
+    // score ranks a page for the given terms. A page only matches if it
+    // contains all terms. Matches in the title and tags weigh more.
+    function score(page, terms) {
+        var title = page.t.toLowerCase();
+        var tags = (page.g || []).join(" ").toLowerCase();
+        var content = page.c.toLowerCase();
+        var total = 0;
+
+        for (var i = 0; i < terms.length; i++) {
+            var s = 10 * count(title, terms[i]) + 5 * count(tags, terms[i]) + count(content, terms[i]);
+            if (s === 0) {
+                return 0;
+            }
+            total += s;
+        }
+        return total;
+    }
+
+    function excerpt(content, term) {
+        var i = Math.max(content.toLowerCase().indexOf(term), 0);
+        var start = Math.max(i - 60, 0);
+        return (start > 0 ? "…" : "") + content.substr(start, 160) + (start + 160 < content.length ? "…" : "");
+    }
+
+    function search(query, section) {
+        var terms = query.toLowerCase().split(/\s+/).filter(Boolean);
+        if (terms.length === 0) {
+            return Promise.resolve([]);
+        }
+        return load(section).then(function (pages) {
+            return pages.map(function (page) {
+                return {page: page, score: score(page, terms)};
+            }).filter(function (r) {
+                return r.score > 0;
+            }).sort(function (a, b) {
+                return b.score - a.score;
+            }).map(function (r) {
+                return {
+                    title: r.page.t,
+                    href: r.page.h,
+                    tags: r.page.g || [],
+                    excerpt: excerpt(r.page.c, terms[0])
+                };
+            });
+        });
+    }
+
+    // bind searches for the value of the given input element on each
+    // input and renders the results as list items into the given element.
+    function bind(input, results, section) {
+        var inputEl = typeof input === "string" ? document.querySelector(input) : input;
+        var resultsEl = typeof results === "string" ? document.querySelector(results) : results;
+        var latest = 0;
+
+        inputEl.addEventListener("input", function () {
+            var current = ++latest;
+            search(inputEl.value, section).then(function (matches) {
+                if (current !== latest) {
+                    return;
+                }
+                resultsEl.innerHTML = "";
+                matches.forEach(function (match) {
+                    var li = document.createElement("li");
+                    var a = document.createElement("a");
+                    var p = document.createElement("p");
+                    a.href = match.href;
+                    a.textContent = match.title;
+                    p.textContent = match.excerpt;
+                    li.appendChild(a);
+                    li.appendChild(p);
+                    resultsEl.appendChild(li);
+                });
+            });
+        });
+    }
+
+    global.verlessSearch = {search: search, bind: bind};
+})(window);
+`
@@ -0,0 +1,229 @@
+// Package search provides and implements the search plugin.
+package search
+
+import (
+	"encoding/json"
+	"html"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/afero"
+	"github.com/verless/verless/config"
+	"github.com/verless/verless/model"
+)
+
+const (
+	// dir is the directory containing the search index and the client
+	// inside the output directory.
+	dir string = "search"
+	// indexFile is the filename of the search index. If the index is
+	// sharded, it is a manifest referencing all shards.
+	indexFile string = "index.json"
+	// clientFile is the filename of the JavaScript search client.
+	clientFile string = "search.js"
+	// shardsDir is the directory containing the shards of a sharded
+	// search index inside the search directory. Keeping the shards in
+	// their own directory prevents a section called index from replacing
+	// the manifest.
+	shardsDir string = "shards"
+	// rootShard is the shard name for pages in the content root. As
+	// content directories starting with an underscore are ignored, it
+	// can't conflict with a section name.
+	rootShard string = "_root"
+)
+
+var (
+	// ignoredElements matches elements whose text isn't visible.
+	ignoredElements = regexp.MustCompile(`(?is)<(script|style)[^>]*>.*?</(script|style)>`)
+	// tags matches all HTML tags.
+	tags = regexp.MustCompile(`<[^>]*>`)
+)
+
+// entry is a page in the search index. The keys are kept short to
+// keep the index compact.
+type entry struct {
+	Title   string   `json:"t"`
+	Href    string   `json:"h"`
+	Tags    []string `json:"g,omitempty"`
+	Content string   `json:"c"`
+
+	section  string
+	language string
+}
+
+// index is a search index or a shard of it.
+type index struct {
+	Pages []entry `json:"pages"`
+}
+
+// manifest references all shards of a sharded search index, keyed by
+// their section name. The shard URLs are relative to the manifest.
+type manifest struct {
+	Shards map[string]string `json:"shards"`
+}
+
+// New creates a new search plugin that writes the search index and the
+// search client to outputDir.
+func New(cfg *config.Config, fs afero.Fs, outputDir string) *search {
+	s := search{
+		sections:  cfg.Search.Sections,
+		fs:        fs,
+		outputDir: outputDir,
+		entries:   make([]entry, 0),
+		languages: make(map[string]string),
+	}
+	return &s
+}
+
+// search is the actual search plugin. It collects the plain text of all
+// pages and writes it into a JSON search index. On multilingual websites,
+// each language gets its own search index.
+type search struct {
+	sections  bool
+	fs        afero.Fs
+	outputDir string
+	entries   []entry
+	// languages maps the codes of all languages to their Href.
+	languages map[string]string
+	mutex     sync.Mutex
+}
+
+// ProcessPage extracts the plain text, title, tags and href of a page
+// and adds them to the search index.
+func (s *search) ProcessPage(page *model.Page) error {
+	if page.Hidden || page.IsCustomListPage() {
+		return nil
+	}
+
+	e := entry{
+		Title:    page.Title,
+		Href:     page.Href,
+		Content:  plainText(string(page.Content)),
+		section:  section(page.Route),
+		language: page.Language,
+	}
+
+	for _, tag := range page.Tags {
+		e.Tags = append(e.Tags, tag.Name)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.entries = append(s.entries, e)
+
+	return nil
+}
+
+// PreWrite registers the language of the site, so that its search index
+// can be written to the language's directory.
+func (s *search) PreWrite(site *model.Site) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.languages[site.Language.Code] = site.Language.Href
+
+	return nil
+}
+
+// PostWrite writes the search index and the search client for each
+// language.
+func (s *search) PostWrite() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Pages are processed concurrently, so the entries are sorted to
+	// produce the same index for each build.
+	sort.Slice(s.entries, func(i, j int) bool {
+		return s.entries[i].Href < s.entries[j].Href
+	})
+
+	entries := make(map[string][]entry, len(s.languages))
+	for code := range s.languages {
+		entries[code] = make([]entry, 0)
+	}
+	for _, e := range s.entries {
+		entries[e.language] = append(entries[e.language], e)
+	}
+
+	for code, languageEntries := range entries {
+		if err := s.writeIndex(path.Join("/", s.languages[code], dir), languageEntries); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeIndex writes the search index for the given entries along with
+// the search client to the given search directory, like /de/search. If
+// sharding is enabled, each top-level content directory gets its own
+// index file in the shards directory and the index file references
+// those shards.
+func (s *search) writeIndex(searchDir string, entries []entry) error {
+	if err := s.fs.MkdirAll(filepath.Join(s.outputDir, searchDir, shardsDir), 0755); err != nil {
+		return err
+	}
+
+	if err := afero.WriteFile(s.fs, filepath.Join(s.outputDir, searchDir, clientFile), []byte(client), 0644); err != nil {
+		return err
+	}
+
+	if !s.sections {
+		return s.write(searchDir, indexFile, index{Pages: entries})
+	}
+
+	shards := make(map[string][]entry)
+	for _, e := range entries {
+		shards[e.section] = append(shards[e.section], e)
+	}
+
+	m := manifest{
+		Shards: make(map[string]string, len(shards)),
+	}
+
+	for section, shardEntries := range shards {
+		name := section
+		if name == "" {
+			name = rootShard
+		}
+		filename := path.Join(shardsDir, name+".json")
+
+		if err := s.write(searchDir, filename, index{Pages: shardEntries}); err != nil {
+			return err
+		}
+
+		m.Shards[section] = filename
+	}
+
+	return s.write(searchDir, indexFile, m)
+}
+
+// write encodes the given value as compact JSON and writes it to the
+// given file inside the given search directory.
+func (s *search) write(searchDir, filename string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return afero.WriteFile(s.fs, filepath.Join(s.outputDir, searchDir, filename), data, 0644)
+}
+
+// plainText converts the HTML content of a page to plain text without
+// any markup and with collapsed whitespace.
+func plainText(content string) string {
+	text := ignoredElements.ReplaceAllString(content, " ")
+	text = tags.ReplaceAllString(text, " ")
+	text = html.UnescapeString(text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// section returns the top-level content directory of the given route,
+// or an empty string for the content root.
+func section(route string) string {
+	return strings.Split(strings.Trim(route, "/"), "/")[0]
+}
@@ -0,0 +1,125 @@
+package search
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/verless/verless/config"
+	"github.com/verless/verless/model"
+	"github.com/verless/verless/test"
+)
+
+var (
+	// testPages is a set of pages used for testing.
+	testPages = []model.Page{
+		{
+			ID: "espresso", Route: "/blog", Href: "/blog/espresso", Title: "Espresso",
+			Tags:    []model.Tag{{Name: "Coffee"}},
+			Content: "<h1>Espresso</h1>\n<p>Making <em>espresso</em> &amp; crema.</p><script>alert(1)</script>",
+		},
+		{ID: "about", Route: "/", Href: "/about", Title: "About", Content: "<p>About me</p>"},
+		{ID: "hidden", Route: "/blog", Href: "/blog/hidden", Title: "Hidden", Hidden: true},
+		{ID: "index", Route: "/blog", Href: "/blog", Title: "Blog"},
+		{ID: "api", Route: "/index", Href: "/index/api", Title: "API", Content: "<p>Reference</p>"},
+	}
+)
+
+// TestSearch_PostWrite checks if the search plugin writes the search
+// index for all visible pages, optionally sharded by section.
+func TestSearch_PostWrite(t *testing.T) {
+	espresso := entry{Title: "Espresso", Href: "/blog/espresso", Tags: []string{"Coffee"}, Content: "Espresso Making espresso & crema."}
+	about := entry{Title: "About", Href: "/about", Content: "About me"}
+	reference := entry{Title: "API", Href: "/index/api", Content: "Reference"}
+
+	tests := map[string]struct {
+		sections bool
+		expected map[string]interface{}
+	}{
+		"single index": {
+			expected: map[string]interface{}{
+				"index.json": index{Pages: []entry{about, espresso, reference}},
+			},
+		},
+		"sharded index": {
+			sections: true,
+			expected: map[string]interface{}{
+				"index.json": manifest{Shards: map[string]string{
+					"":      "shards/_root.json",
+					"blog":  "shards/blog.json",
+					"index": "shards/index.json",
+				}},
+				"shards/_root.json": index{Pages: []entry{about}},
+				"shards/blog.json":  index{Pages: []entry{espresso}},
+				"shards/index.json": index{Pages: []entry{reference}},
+			},
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		fs := afero.NewMemMapFs()
+
+		cfg := config.Config{}
+		cfg.Search.Sections = testCase.sections
+
+		s := New(&cfg, fs, "/out")
+
+		for _, page := range testPages {
+			page := page
+			test.Ok(t, s.ProcessPage(&page))
+		}
+
+		test.Ok(t, s.PostWrite())
+
+		for file, expected := range testCase.expected {
+			data, err := afero.ReadFile(fs, "/out/search/"+file)
+			test.Ok(t, err)
+
+			expectedData, err := json.Marshal(expected)
+			test.Ok(t, err)
+
+			test.Equals(t, string(expectedData), string(data))
+		}
+
+		exists, err := afero.Exists(fs, "/out/search/search.js")
+		test.Ok(t, err)
+		test.Assert(t, exists, "the search client has to be written")
+	}
+}
+
+// TestSearch_PostWrite_languages checks if the search plugin writes a
+// separate search index for each language.
+func TestSearch_PostWrite_languages(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s := New(&config.Config{}, fs, "/out")
+
+	languages := []model.Language{
+		{Code: "en", Href: ""},
+		{Code: "de", Href: "/de"},
+	}
+
+	for _, language := range languages {
+		page := model.Page{Route: "/", Href: language.Href + "/about", Title: language.Code, Language: language.Code}
+		test.Ok(t, s.ProcessPage(&page))
+		test.Ok(t, s.PreWrite(&model.Site{Language: language}))
+	}
+
+	test.Ok(t, s.PostWrite())
+
+	expected := map[string]index{
+		"/out/search/index.json":    {Pages: []entry{{Title: "en", Href: "/about"}}},
+		"/out/de/search/index.json": {Pages: []entry{{Title: "de", Href: "/de/about"}}},
+	}
+
+	for file, expected := range expected {
+		data, err := afero.ReadFile(fs, file)
+		test.Ok(t, err)
+
+		expectedData, err := json.Marshal(expected)
+		test.Ok(t, err)
+
+		test.Equals(t, string(expectedData), string(data))
+	}
+}
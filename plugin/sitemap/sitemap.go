@@ -0,0 +1,292 @@
+// Package sitemap provides and implements the sitemap plugin.
+package sitemap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/verless/verless/model"
+	"github.com/verless/verless/tree"
+)
+
+const (
+	// filename is the filename of the sitemap or the sitemap index.
+	filename string = "sitemap.xml"
+	// childFilename is the filename pattern for child sitemaps that are
+	// referenced by the sitemap index.
+	childFilename string = "sitemap-%d.xml"
+	// namespace is the XML namespace of the sitemap protocol.
+	namespace string = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	// maxURLs is the maximum number of URLs allowed in a single sitemap.
+	maxURLs int = 50000
+	// dateFormat is the date format used for lastmod.
+	dateFormat string = "2006-01-02"
+
+	lastModKey    string = "LastMod"
+	changeFreqKey string = "ChangeFreq"
+	priorityKey   string = "Priority"
+)
+
+var (
+	// changeFreqs contains all valid values for changefreq.
+	changeFreqs = []string{"always", "hourly", "daily", "weekly", "monthly", "yearly", "never"}
+)
+
+// urlSet is the root element of a sitemap.
+type urlSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	URLs    []url    `xml:"url"`
+}
+
+// url represents a single page in a sitemap.
+type url struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod,omitempty"`
+	ChangeFreq string `xml:"changefreq,omitempty"`
+	Priority   string `xml:"priority,omitempty"`
+}
+
+// sitemapIndex is the root element of a sitemap index.
+type sitemapIndex struct {
+	XMLName  xml.Name  `xml:"sitemapindex"`
+	Xmlns    string    `xml:"xmlns,attr"`
+	Sitemaps []sitemap `xml:"sitemap"`
+}
+
+// sitemap represents a child sitemap in a sitemap index.
+type sitemap struct {
+	Loc string `xml:"loc"`
+}
+
+// New creates a new sitemap plugin that generates a sitemap for the
+// website with the provided metadata and stores it in outputDir.
+func New(meta *model.Meta, fs afero.Fs, outputDir string) *sitemapPlugin {
+	s := sitemapPlugin{
+		meta:      meta,
+		fs:        fs,
+		outputDir: outputDir,
+		maxURLs:   maxURLs,
+		urls:      make([]url, 0),
+	}
+	return &s
+}
+
+// sitemapPlugin is the actual sitemap plugin. It collects the URLs of
+// all pages and list pages and writes them into a sitemap.
+type sitemapPlugin struct {
+	meta      *model.Meta
+	fs        afero.Fs
+	outputDir string
+	maxURLs   int
+	urls      []url
+}
+
+// ProcessPage isn't needed by the sitemap plugin.
+func (s *sitemapPlugin) ProcessPage(_ *model.Page) error {
+	return nil
+}
+
+// PreWrite collects the URLs of all visible pages and list pages in the
+// site model, including the list pages generated for taxonomies. On
+// multilingual websites, the sitemap contains the URLs of all languages.
+func (s *sitemapPlugin) PreWrite(site *model.Site) error {
+	err := tree.Walk(site.Root, func(_ string, node tree.Node) error {
+		n := node.(*model.Node)
+
+		if !n.ListPage.Hidden {
+			if err := s.add(&n.ListPage.Page, n.ListPage.Route); err != nil {
+				return err
+			}
+		}
+
+		for i := range n.Pages {
+			if n.Pages[i].Hidden {
+				continue
+			}
+			if err := s.add(&n.Pages[i], n.Pages[i].Href); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, -1)
+
+	if err != nil {
+		return err
+	}
+
+	// The site model is a tree of maps, so the URLs are sorted to produce
+	// the same sitemap for each build.
+	sort.Slice(s.urls, func(i, j int) bool {
+		return s.urls[i].Loc < s.urls[j].Loc
+	})
+
+	return nil
+}
+
+// PostWrite writes the sitemap into the output directory. If there are
+// more URLs than allowed in a single sitemap, the URLs are split into
+// multiple child sitemaps that are referenced by a sitemap index.
+func (s *sitemapPlugin) PostWrite() error {
+	if len(s.urls) <= s.maxURLs {
+		return s.write(filename, urlSet{Xmlns: namespace, URLs: s.urls})
+	}
+
+	index := sitemapIndex{
+		Xmlns: namespace,
+	}
+
+	for i := 0; i*s.maxURLs < len(s.urls); i++ {
+		end := (i + 1) * s.maxURLs
+		if end > len(s.urls) {
+			end = len(s.urls)
+		}
+
+		name := fmt.Sprintf(childFilename, i+1)
+
+		if err := s.write(name, urlSet{Xmlns: namespace, URLs: s.urls[i*s.maxURLs : end]}); err != nil {
+			return err
+		}
+
+		index.Sitemaps = append(index.Sitemaps, sitemap{
+			Loc: s.loc("/" + name),
+		})
+	}
+
+	return s.write(filename, index)
+}
+
+// add adds a page with the given href to the sitemap. The optional
+// values for lastmod, changefreq and priority are read from the page's
+// front matter.
+func (s *sitemapPlugin) add(page *model.Page, href string) error {
+	u := url{
+		Loc: s.loc(href),
+	}
+
+	lastMod, err := lastMod(page)
+	if err != nil {
+		return pageError(page, err)
+	}
+	if !lastMod.IsZero() {
+		u.LastMod = lastMod.Format(dateFormat)
+	}
+
+	if u.ChangeFreq, err = changeFreq(page); err != nil {
+		return pageError(page, err)
+	}
+
+	if u.Priority, err = priority(page); err != nil {
+		return pageError(page, err)
+	}
+
+	s.urls = append(s.urls, u)
+
+	return nil
+}
+
+// loc returns the absolute URL for the given href.
+func (s *sitemapPlugin) loc(href string) string {
+	return strings.TrimSuffix(s.meta.Base, "/") + href
+}
+
+// write encodes the given sitemap or sitemap index as XML and writes it
+// to the given file inside the output directory.
+func (s *sitemapPlugin) write(name string, v interface{}) error {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.outputDir, name)
+
+	return afero.WriteFile(s.fs, path, append([]byte(xml.Header), data...), 0644)
+}
+
+// lastMod returns the date the page has been modified the last time. If
+// the page doesn't provide a LastMod date, its Date is used.
+func lastMod(page *model.Page) (time.Time, error) {
+	val := page.Param(lastModKey)
+	if val == nil {
+		return page.Date, nil
+	}
+
+	str, ok := val.(string)
+	if !ok {
+		return time.Time{}, invalidValue(lastModKey, "date in the form YYYY-MM-DD", val)
+	}
+
+	if date, err := time.Parse(dateFormat, str); err == nil {
+		return date, nil
+	}
+	if date, err := time.Parse(time.RFC3339, str); err == nil {
+		return date, nil
+	}
+
+	return time.Time{}, invalidValue(lastModKey, "date in the form YYYY-MM-DD", val)
+}
+
+// changeFreq returns the change frequency provided by the page.
+func changeFreq(page *model.Page) (string, error) {
+	val := page.Param(changeFreqKey)
+	if val == nil {
+		return "", nil
+	}
+
+	str, ok := val.(string)
+	if ok {
+		for _, changeFreq := range changeFreqs {
+			if strings.ToLower(str) == changeFreq {
+				return changeFreq, nil
+			}
+		}
+	}
+
+	return "", invalidValue(changeFreqKey, "one of "+strings.Join(changeFreqs, ", "), val)
+}
+
+// priority returns the priority provided by the page.
+func priority(page *model.Page) (string, error) {
+	val := page.Param(priorityKey)
+
+	var p float64
+
+	switch v := val.(type) {
+	case nil:
+		return "", nil
+	case int:
+		p = float64(v)
+	case int64:
+		p = float64(v)
+	case float64:
+		p = v
+	default:
+		return "", invalidValue(priorityKey, "number between 0.0 and 1.0", val)
+	}
+
+	if p < 0 || p > 1 {
+		return "", invalidValue(priorityKey, "number between 0.0 and 1.0", val)
+	}
+
+	return strconv.FormatFloat(p, 'f', -1, 64), nil
+}
+
+func invalidValue(key, expected string, val interface{}) error {
+	return fmt.Errorf("invalid value for key %s: expected %s, got %v", key, expected, val)
+}
+
+// pageError prefixes an error with the source file of the page, if
+// there is any.
+func pageError(page *model.Page, err error) error {
+	if page.SourcePath() == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %w", page.SourcePath(), err)
+}
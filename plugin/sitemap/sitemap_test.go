@@ -0,0 +1,152 @@
+package sitemap
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/verless/verless/cache"
+	"github.com/verless/verless/model"
+	"github.com/verless/verless/test"
+	"github.com/verless/verless/tree"
+)
+
+// testSite creates a site model with a list page, a visible page and a
+// hidden page.
+func testSite(t *testing.T, params map[string]interface{}) *model.Site {
+	site := model.NewSite()
+
+	blog := model.NewNode()
+	blog.ListPage.Route = "/blog"
+	blog.Pages = []model.Page{
+		{Href: "/blog/espresso", Date: time.Date(2020, 8, 14, 0, 0, 0, 0, time.UTC), Params: params},
+		{Href: "/blog/hidden", Hidden: true},
+	}
+
+	site.Root.ListPage.Route = "/"
+	test.Ok(t, tree.CreateNode("/blog", site.Root, blog))
+
+	return &site
+}
+
+// TestSitemap_PreWrite checks if the sitemap plugin collects all visible
+// pages along with their optional front matter values.
+func TestSitemap_PreWrite(t *testing.T) {
+	tests := map[string]struct {
+		params        map[string]interface{}
+		expected      []url
+		expectedError bool
+	}{
+		"default values": {
+			expected: []url{
+				{Loc: "https://example.com/"},
+				{Loc: "https://example.com/blog"},
+				{Loc: "https://example.com/blog/espresso", LastMod: "2020-08-14"},
+			},
+		},
+		"front matter values": {
+			params: map[string]interface{}{"lastmod": "2020-09-01", "ChangeFreq": "Weekly", "Priority": 0.8},
+			expected: []url{
+				{Loc: "https://example.com/"},
+				{Loc: "https://example.com/blog"},
+				{Loc: "https://example.com/blog/espresso", LastMod: "2020-09-01", ChangeFreq: "weekly", Priority: "0.8"},
+			},
+		},
+		"invalid change frequency": {
+			params:        map[string]interface{}{"ChangeFreq": "sometimes"},
+			expectedError: true,
+		},
+		"invalid priority": {
+			params:        map[string]interface{}{"Priority": 2},
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		s := New(&model.Meta{Base: "https://example.com/"}, afero.NewMemMapFs(), "/out")
+
+		err := s.PreWrite(testSite(t, testCase.params))
+		if testCase.expectedError {
+			test.Assert(t, err != nil, "invalid values should return an error")
+			continue
+		}
+		test.Ok(t, err)
+
+		test.Equals(t, testCase.expected, s.urls)
+	}
+}
+
+// TestSitemap_PostWrite checks if the sitemap plugin writes a single
+// sitemap or a sitemap index if there are too many URLs.
+func TestSitemap_PostWrite(t *testing.T) {
+	tests := map[string]struct {
+		maxURLs  int
+		expected map[string][]string
+	}{
+		"single sitemap": {
+			maxURLs: maxURLs,
+			expected: map[string][]string{
+				"/out/sitemap.xml": {"<urlset", "<loc>https://example.com/blog/espresso</loc>"},
+			},
+		},
+		"sitemap index": {
+			maxURLs: 2,
+			expected: map[string][]string{
+				"/out/sitemap.xml":   {"<sitemapindex", "<loc>https://example.com/sitemap-1.xml</loc>", "<loc>https://example.com/sitemap-2.xml</loc>"},
+				"/out/sitemap-1.xml": {"<urlset", "<loc>https://example.com/</loc>", "<loc>https://example.com/blog</loc>"},
+				"/out/sitemap-2.xml": {"<urlset", "<loc>https://example.com/blog/espresso</loc>"},
+			},
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		fs := afero.NewMemMapFs()
+		test.Ok(t, fs.MkdirAll("/out", 0755))
+
+		s := New(&model.Meta{Base: "https://example.com"}, fs, "/out")
+		s.maxURLs = testCase.maxURLs
+
+		test.Ok(t, s.PreWrite(testSite(t, nil)))
+		test.Ok(t, s.PostWrite())
+
+		for file, contents := range testCase.expected {
+			data, err := afero.ReadFile(fs, file)
+			test.Ok(t, err)
+
+			for _, content := range contents {
+				test.Assert(t, strings.Contains(string(data), content), "%s should contain %s", file, content)
+			}
+		}
+	}
+}
+
+// TestSitemap_PostWrite_stale checks if child sitemaps of a previous
+// build that aren't written anymore are recorded as stale outputs, so
+// that they are pruned.
+func TestSitemap_PostWrite_stale(t *testing.T) {
+	memMapFs := afero.NewMemMapFs()
+	test.Ok(t, memMapFs.MkdirAll("/out", 0755))
+
+	c := cache.New(memMapFs, "/project", "key")
+
+	s := New(&model.Meta{Base: "https://example.com"}, c.Fs(memMapFs), "/out")
+	s.maxURLs = 1
+
+	test.Ok(t, s.PreWrite(testSite(t, nil)))
+	test.Ok(t, s.PostWrite())
+	test.Ok(t, c.Commit())
+
+	// With fewer URLs per build, fewer child sitemaps are written.
+	s = New(&model.Meta{Base: "https://example.com"}, c.Fs(memMapFs), "/out")
+	s.maxURLs = 2
+
+	test.Ok(t, s.PreWrite(testSite(t, nil)))
+	test.Ok(t, s.PostWrite())
+
+	test.Equals(t, []string{"/out/sitemap-3.xml"}, c.Stale())
+}
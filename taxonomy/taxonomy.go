@@ -0,0 +1,293 @@
+// Package taxonomy provides the taxonomy subsystem that groups pages by
+// terms like tags or categories.
+//
+// Each taxonomy is declared in the project configuration. The terms of a
+// page are read from the taxonomy's front matter key, and for each term,
+// a list page containing all pages with that term is generated under the
+// taxonomy's URL base. A terms index page lists all terms.
+package taxonomy
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/verless/verless/config"
+	"github.com/verless/verless/model"
+	"github.com/verless/verless/parser"
+	"github.com/verless/verless/tree"
+)
+
+const (
+	// Tags is the name of the default taxonomy. Its terms are available
+	// as Page.Tags for backwards compatibility.
+	Tags string = "tags"
+)
+
+// Taxonomies maintains all declared taxonomies along with their terms.
+// All methods are safe for concurrent usage.
+type Taxonomies struct {
+	taxonomies []*taxonomy
+	language   model.Language
+}
+
+// taxonomy represents a single taxonomy like tags.
+type taxonomy struct {
+	name     string
+	cfg      config.Taxonomy
+	terms    map[string]*model.ListPage
+	language model.Language
+	// titleSources contains the source path of the page each term's
+	// title has been taken from.
+	titleSources map[string]string
+	mutex        sync.Mutex
+}
+
+// New creates the taxonomies declared in the given configuration. Unset
+// configuration values are replaced with their defaults.
+func New(cfgs map[string]config.Taxonomy) *Taxonomies {
+	return NewLanguage(cfgs, model.Language{})
+}
+
+// NewLanguage creates the taxonomies for the site of the given language.
+// The URLs of all terms start with the language's Href, like /de/tags.
+func NewLanguage(cfgs map[string]config.Taxonomy, language model.Language) *Taxonomies {
+	t := Taxonomies{
+		taxonomies: make([]*taxonomy, 0, len(cfgs)),
+		language:   language,
+	}
+
+	for name, cfg := range cfgs {
+		name = strings.ToLower(name)
+
+		if cfg.Key == "" {
+			cfg.Key = name
+		}
+		cfg.Base = Base(name, cfg)
+
+		t.taxonomies = append(t.taxonomies, &taxonomy{
+			name:         name,
+			cfg:          cfg,
+			terms:        make(map[string]*model.ListPage),
+			language:     language,
+			titleSources: make(map[string]string),
+		})
+	}
+
+	// Process the taxonomies in a stable order.
+	sort.Slice(t.taxonomies, func(i, j int) bool {
+		return t.taxonomies[i].name < t.taxonomies[j].name
+	})
+
+	return &t
+}
+
+// ProcessPage reads the terms of all taxonomies from the page's front
+// matter, assigns them to the page and adds the page to the list page of
+// each term.
+//
+// ProcessPage has to be invoked before the page is registered in the site
+// model, so that the assigned terms are part of the registered page.
+func (t *Taxonomies) ProcessPage(page *model.Page) error {
+	for _, tax := range t.taxonomies {
+		terms, err := tax.readTerms(page)
+		if err != nil {
+			return err
+		}
+		if len(terms) == 0 {
+			continue
+		}
+
+		if page.Taxonomies == nil {
+			page.Taxonomies = make(map[string][]model.Tag)
+		}
+		page.Taxonomies[tax.name] = terms
+
+		if tax.name == Tags {
+			page.Tags = terms
+		}
+
+		tax.add(page, terms)
+	}
+
+	return nil
+}
+
+// PreWrite registers the list page of each term and the terms index page
+// of each taxonomy in the site model.
+func (t *Taxonomies) PreWrite(site *model.Site) error {
+	for _, tax := range t.taxonomies {
+		if err := tax.register(site); err != nil {
+			return err
+		}
+	}
+
+	// Intermediate nodes for URL bases like /blog/categories don't have
+	// a route yet.
+	return tree.Walk(site.Root, func(route string, node tree.Node) error {
+		n := node.(*model.Node)
+		if n.ListPage.Route == "" {
+			n.ListPage.Route = path.Join(t.language.Href, route)
+		}
+		return nil
+	}, -1)
+}
+
+// readTerms reads the terms from the page's front matter. If the page
+// doesn't provide any terms, readTerms returns nil. Terms that aren't
+// strings are reported as parser.MetadataErrors.
+func (tax *taxonomy) readTerms(page *model.Page) ([]model.Tag, error) {
+	var (
+		names []string
+		errs  parser.MetadataErrors
+	)
+
+	switch value := page.Param(tax.cfg.Key).(type) {
+	case nil:
+	case string:
+		names = []string{value}
+	case []interface{}:
+		for i, item := range value {
+			name, ok := item.(string)
+			if !ok {
+				errs = append(errs, parser.NewMetadataError(fmt.Sprintf("%s[%d]", tax.cfg.Key, i), "string", item))
+				continue
+			}
+			names = append(names, name)
+		}
+	default:
+		errs = append(errs, parser.NewMetadataError(tax.cfg.Key, "string or list", value))
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	terms := make([]model.Tag, 0, len(names))
+
+	for _, name := range names {
+		terms = append(terms, model.Tag{
+			Name: name,
+			Href: path.Join(tax.language.Href, tax.cfg.Base, Slug(name)),
+		})
+	}
+
+	return terms, nil
+}
+
+// add adds a page to the list pages of the given terms. As terms may be
+// spelled differently, like Go and go, the title of a term's list page is
+// taken from the page with the lowest source path, so that it doesn't
+// depend on the order in which the pages are processed.
+func (tax *taxonomy) add(page *model.Page, terms []model.Tag) {
+	tax.mutex.Lock()
+	defer tax.mutex.Unlock()
+
+	for _, term := range terms {
+		slug := Slug(term.Name)
+
+		listPage, exists := tax.terms[slug]
+		if !exists {
+			listPage = &model.ListPage{
+				Pages: make([]*model.Page, 0),
+				Page: model.Page{
+					Route: term.Href,
+					Title: term.Name,
+					Type:  listType(tax.cfg.Template),
+				},
+				Taxonomy: tax.name,
+			}
+			tax.terms[slug] = listPage
+			tax.titleSources[slug] = page.SourcePath()
+		}
+
+		source := tax.titleSources[slug]
+		if page.SourcePath() < source || (page.SourcePath() == source && term.Name < listPage.Title) {
+			listPage.Title = term.Name
+			tax.titleSources[slug] = page.SourcePath()
+		}
+
+		listPage.Pages = append(listPage.Pages, page)
+	}
+}
+
+// register creates a node for the terms index page and for each term.
+func (tax *taxonomy) register(site *model.Site) error {
+	index := model.NewNode()
+	index.ListPage.Route = path.Join(tax.language.Href, tax.cfg.Base)
+	index.ListPage.Title = tax.name
+	index.ListPage.Type = listType(tax.cfg.TermsTemplate)
+
+	slugs := make([]string, 0, len(tax.terms))
+	for slug := range tax.terms {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+
+	for _, slug := range slugs {
+		listPage := tax.terms[slug]
+
+		// Pages are processed concurrently, so their order has to be
+		// restored to produce the same output for each build.
+		if err := model.SortPages(listPage.Pages, tax.cfg.SortBy, tax.cfg.SortOrder); err != nil {
+			return fmt.Errorf("taxonomy %s: %w", tax.name, err)
+		}
+
+		// Each term is listed as a page on the terms index page.
+		index.ListPage.Pages = append(index.ListPage.Pages, &model.Page{
+			Route: index.ListPage.Route,
+			ID:    slug,
+			Href:  listPage.Route,
+			Title: listPage.Title,
+		})
+	}
+
+	if err := tree.CreateNode(tax.cfg.Base, site.Root, index); err != nil {
+		return err
+	}
+
+	// The nodes are created relative to the language, whereas the routes
+	// of the list pages include the language.
+	for _, slug := range slugs {
+		node := model.NewNode()
+		node.ListPage = *tax.terms[slug]
+
+		if err := tree.CreateNode(path.Join(tax.cfg.Base, slug), site.Root, node); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Base returns the URL base of the taxonomy with the given name and
+// configuration, like /tags.
+func Base(name string, cfg config.Taxonomy) string {
+	if cfg.Base == "" {
+		return "/" + strings.ToLower(name)
+	}
+	return "/" + strings.Trim(cfg.Base, "/")
+}
+
+// Slug converts a term like "Making Coffee" into its URL form, like
+// making-coffee. For terms without leading, trailing or repeated spaces,
+// this is the same URL as generated by the former tags plugin.
+func Slug(term string) string {
+	return strings.ToLower(strings.Join(strings.Fields(term), "-"))
+}
+
+// listType returns the page type for list pages rendered with the given
+// template. If the template is empty, the default list page template is
+// used.
+func listType(template string) *model.Type {
+	if template == "" {
+		return nil
+	}
+	return &model.Type{Template: template}
+}
@@ -0,0 +1,231 @@
+package taxonomy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/verless/verless/config"
+	"github.com/verless/verless/model"
+	"github.com/verless/verless/parser"
+	"github.com/verless/verless/test"
+	"github.com/verless/verless/tree"
+)
+
+var (
+	// testPages is a set of pages used for testing.
+	testPages = []model.Page{
+		{ID: "page-0", Route: "/route-0", Href: "/route-0/page-0", Params: map[string]interface{}{
+			"Tags":       []interface{}{"t-1", "Making Coffee"},
+			"Categories": "Guides",
+		}},
+		{ID: "page-1", Route: "/route-1", Href: "/route-1/page-1", Params: map[string]interface{}{
+			"Tags": []interface{}{"t-1", "t-3"},
+		}},
+		{ID: "page-2", Route: "/route-2", Href: "/route-2/page-2", Params: map[string]interface{}{
+			"Categories": "Guides",
+		}},
+		{ID: "page-3", Route: "/route-3", Href: "/route-3/page-3"},
+	}
+
+	// testConfig declares the taxonomies used for testing.
+	testConfig = map[string]config.Taxonomy{
+		"tags": {},
+		"categories": {
+			Base:          "/blog/categories",
+			Template:      "category.html",
+			TermsTemplate: "categories.html",
+		},
+	}
+)
+
+// TestTaxonomies_ProcessPage checks if the terms of each taxonomy are
+// assigned to the pages and if the pages are added to each term.
+func TestTaxonomies_ProcessPage(t *testing.T) {
+	tests := map[string]struct {
+		page       model.Page
+		taxonomies map[string][]model.Tag
+	}{
+		"tags and categories": {
+			page: testPages[0],
+			taxonomies: map[string][]model.Tag{
+				"tags": {
+					{Name: "t-1", Href: "/tags/t-1"},
+					{Name: "Making Coffee", Href: "/tags/making-coffee"},
+				},
+				"categories": {
+					{Name: "Guides", Href: "/blog/categories/guides"},
+				},
+			},
+		},
+		"no terms": {
+			page: testPages[3],
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		taxonomies := New(testConfig)
+		page := testCase.page
+
+		test.Ok(t, taxonomies.ProcessPage(&page))
+		test.Equals(t, testCase.taxonomies, page.Taxonomies)
+		test.Equals(t, testCase.taxonomies["tags"], page.Tags)
+	}
+}
+
+// TestTaxonomies_PreWrite checks if each taxonomy registers a terms
+// index page and a list page for each term in the site model.
+func TestTaxonomies_PreWrite(t *testing.T) {
+	taxonomies := New(testConfig)
+
+	for i := range testPages {
+		page := testPages[i]
+		test.Ok(t, taxonomies.ProcessPage(&page))
+	}
+
+	site := model.NewSite()
+	test.Ok(t, taxonomies.PreWrite(&site))
+
+	tests := map[string]struct {
+		path     string
+		pages    []string
+		template string
+	}{
+		"tags index": {
+			path:  "/tags",
+			pages: []string{"/tags/making-coffee", "/tags/t-1", "/tags/t-3"},
+		},
+		"tag": {
+			path:  "/tags/t-1",
+			pages: []string{"/route-0/page-0", "/route-1/page-1"},
+		},
+		"categories index": {
+			path:     "/blog/categories",
+			pages:    []string{"/blog/categories/guides"},
+			template: "categories.html",
+		},
+		"category": {
+			path:     "/blog/categories/guides",
+			pages:    []string{"/route-0/page-0", "/route-2/page-2"},
+			template: "category.html",
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		node, err := tree.ResolveNode(testCase.path, site.Root)
+		test.Ok(t, err)
+
+		listPage := node.(*model.Node).ListPage
+		test.Equals(t, testCase.path, listPage.Route)
+
+		hrefs := make([]string, len(listPage.Pages))
+		for i, p := range listPage.Pages {
+			hrefs[i] = p.Href
+		}
+		test.Equals(t, testCase.pages, hrefs)
+
+		if testCase.template != "" {
+			test.Equals(t, testCase.template, listPage.Type.Template)
+		}
+	}
+
+	// The intermediate /blog node must have a route as well.
+	test.Equals(t, "/blog", site.Root.Children()["blog"].(*model.Node).ListPage.Route)
+}
+
+// TestSlug checks if terms are converted into their URL form.
+func TestSlug(t *testing.T) {
+	tests := map[string]string{
+		"Coffee":         "coffee",
+		"Making Coffee":  "making-coffee",
+		"  Latte  Art  ": "latte-art",
+	}
+
+	for term, expected := range tests {
+		t.Log(term)
+		test.Equals(t, expected, Slug(term))
+	}
+}
+
+// TestTaxonomies_ProcessPage_invalidTerms checks if terms that aren't
+// strings are reported as metadata errors.
+func TestTaxonomies_ProcessPage_invalidTerms(t *testing.T) {
+	tests := map[string]interface{}{
+		"number":         2020,
+		"number in list": []interface{}{"Guides", 2020},
+		"map":            map[string]interface{}{"Name": "Guides"},
+		"bool in list":   []interface{}{true},
+	}
+
+	for name, value := range tests {
+		t.Log(name)
+
+		page := model.Page{Params: map[string]interface{}{"Categories": value}}
+
+		err := New(testConfig).ProcessPage(&page)
+
+		var errs parser.MetadataErrors
+		test.Assert(t, errors.As(err, &errs), "expected MetadataErrors, got %v", err)
+	}
+}
+
+// TestTaxonomies_PreWrite_title checks if the title of a term spelled
+// differently by multiple pages is taken from the page with the lowest
+// source path, regardless of the processing order.
+func TestTaxonomies_PreWrite_title(t *testing.T) {
+	pages := []model.Page{
+		{ID: "b", Href: "/b", Params: map[string]interface{}{"Tags": "go"}},
+		{ID: "a", Href: "/a", Params: map[string]interface{}{"Tags": "Go"}},
+		{ID: "c", Href: "/c", Params: map[string]interface{}{"Tags": "GO"}},
+	}
+	for i := range pages {
+		pages[i].SetSource("/content/"+pages[i].ID+".md", "")
+	}
+
+	orders := [][]int{{0, 1, 2}, {2, 1, 0}, {1, 0, 2}}
+
+	for _, order := range orders {
+		t.Log(order)
+
+		taxonomies := New(testConfig)
+		for _, i := range order {
+			page := pages[i]
+			test.Ok(t, taxonomies.ProcessPage(&page))
+		}
+
+		site := model.NewSite()
+		test.Ok(t, taxonomies.PreWrite(&site))
+
+		node, err := tree.ResolveNode("/tags/go", site.Root)
+		test.Ok(t, err)
+		test.Equals(t, "Go", node.(*model.Node).ListPage.Title)
+	}
+}
+
+// TestTaxonomies_PreWrite_language checks if the terms of a language are
+// registered relative to the language while their URLs and routes start
+// with the language's Href.
+func TestTaxonomies_PreWrite_language(t *testing.T) {
+	taxonomies := NewLanguage(testConfig, model.Language{Code: "de", Href: "/de"})
+
+	page := testPages[0]
+	test.Ok(t, taxonomies.ProcessPage(&page))
+	test.Equals(t, "/de/tags/t-1", page.Tags[0].Href)
+
+	site := model.NewSite()
+	test.Ok(t, taxonomies.PreWrite(&site))
+
+	node, err := tree.ResolveNode("/tags/t-1", site.Root)
+	test.Ok(t, err)
+	test.Equals(t, "/de/tags/t-1", node.(*model.Node).ListPage.Route)
+
+	index, err := tree.ResolveNode("/tags", site.Root)
+	test.Ok(t, err)
+	test.Equals(t, "/de/tags", index.(*model.Node).ListPage.Route)
+	test.Equals(t, "/de/tags/making-coffee", index.(*model.Node).ListPage.Pages[0].Href)
+
+	test.Equals(t, "/de/blog", site.Root.Children()["blog"].(*model.Node).ListPage.Route)
+}
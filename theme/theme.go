@@ -26,7 +26,14 @@ const (
 	Default          string = "default"
 	PageTemplate     string = "page.html"
 	ListPageTemplate string = "list-page.html"
+	TaxonomyTemplate string = "taxonomy.html"
 	configFilename   string = "theme"
+	// BaseTemplate is the base layout whose blocks are filled by other
+	// templates.
+	BaseTemplate string = "baseof.html"
+	// PartialsDir is the directory inside the templates directory that
+	// contains templates included by all other templates.
+	PartialsDir string = "partials"
 )
 
 // Path returns the directory path for the theme with the given name
@@ -0,0 +1,520 @@
+package tpl
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/verless/verless/model"
+	"github.com/verless/verless/taxonomy"
+	"github.com/yuin/goldmark"
+)
+
+const (
+	// dateLayout is the layout of dates without a time accepted by
+	// dateFormat.
+	dateLayout string = "2006-01-02"
+)
+
+var (
+	// funcs contains the built-in functions that are available in all
+	// templates. absURL is added by Funcs, as it depends on the website.
+	funcs = template.FuncMap{
+		// Date functions.
+		"now":        time.Now,
+		"dateFormat": dateFormat,
+		// URL functions.
+		"relURL": relURL,
+		// String functions.
+		"lower":     strings.ToLower,
+		"upper":     strings.ToUpper,
+		"title":     strings.Title,
+		"trim":      strings.TrimSpace,
+		"replace":   replace,
+		"contains":  strings.Contains,
+		"hasPrefix": strings.HasPrefix,
+		"hasSuffix": strings.HasSuffix,
+		"split":     strings.Split,
+		"join":      join,
+		"truncate":  truncate,
+		"slugify":   taxonomy.Slug,
+		"plainify":  plainify,
+		"jsonify":   jsonify,
+		// Math functions.
+		"add": add,
+		"sub": sub,
+		"mul": mul,
+		"div": div,
+		"mod": mod,
+		// HTML functions.
+		"safeHTML":    safeHTML,
+		"markdownify": markdownify,
+		// Collection functions.
+		"first":   first,
+		"last":    last,
+		"where":   where,
+		"groupBy": groupBy,
+		"in":      in,
+		"sortBy":  sortBy,
+		"reverse": reverse,
+	}
+
+	// htmlTags matches all HTML tags removed by plainify.
+	htmlTags = regexp.MustCompile(`<[^>]*>`)
+
+	// markdown is the Markdown converter used by markdownify.
+	markdown = goldmark.New()
+
+	// ErrDivisionByZero is returned by div and mod if the divisor is 0.
+	ErrDivisionByZero = errors.New("division by zero")
+)
+
+// PageGroup is a group of pages returned by groupBy.
+type PageGroup struct {
+	Key   interface{}
+	Pages []*model.Page
+}
+
+// Funcs returns a new map of all built-in template functions. absURL
+// builds absolute URLs using the given base URL of the website, like
+// https://example.com. Functions may be added to the returned map, for
+// example by plugins, without affecting other websites.
+func Funcs(base string) template.FuncMap {
+	fm := make(template.FuncMap, len(funcs)+1)
+	for name, fn := range funcs {
+		fm[name] = fn
+	}
+	fm["absURL"] = absURL(strings.TrimSuffix(base, "/"))
+	return fm
+}
+
+// dateFormat formats a date using a Go layout, for example
+// {{dateFormat "Jan 2 2006" .Page.Date}}. Dates may also be provided as
+// RFC 3339 strings or as dates like 2020-01-02, which is how front matter
+// dates are stored in Page.Params.
+func dateFormat(layout string, date interface{}) (string, error) {
+	switch d := date.(type) {
+	case time.Time:
+		return d.Format(layout), nil
+	case *time.Time:
+		return d.Format(layout), nil
+	case string:
+		t, err := time.Parse(time.RFC3339, d)
+		if err != nil {
+			if t, dateErr := time.Parse(dateLayout, d); dateErr == nil {
+				return t.Format(layout), nil
+			}
+			return "", err
+		}
+		return t.Format(layout), nil
+	}
+	return "", fmt.Errorf("invalid date %v", date)
+}
+
+// absURL returns a function that converts a path like /blog/coffee into
+// an absolute URL using the given base URL. URLs that already are
+// absolute are not modified.
+func absURL(baseURL string) func(href string) string {
+	return func(href string) string {
+		if strings.Contains(href, "://") {
+			return href
+		}
+		return baseURL + relURL(href)
+	}
+}
+
+// relURL converts a path like blog/coffee into a path relative to the
+// website root, like /blog/coffee.
+func relURL(href string) string {
+	if strings.Contains(href, "://") {
+		return href
+	}
+
+	rel := path.Join("/", href)
+
+	// Keep trailing slashes of directories like /blog/.
+	if strings.HasSuffix(href, "/") && rel != "/" {
+		rel += "/"
+	}
+
+	return rel
+}
+
+// replace replaces all occurrences of old with new in the given string,
+// for example {{.Page.Title | replace "Coffee" "Tea"}}.
+func replace(old, new, s string) string {
+	return strings.ReplaceAll(s, old, new)
+}
+
+// join concatenates the string representations of all items of a slice
+// using the given separator, for example {{join ", " .Page.Tags}}.
+func join(sep string, items interface{}) (string, error) {
+	v, err := sliceValue(items)
+	if err != nil {
+		return "", err
+	}
+
+	parts := make([]string, v.Len())
+	for i := range parts {
+		parts[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+
+	return strings.Join(parts, sep), nil
+}
+
+// truncate shortens a string to the given number of characters and adds
+// an ellipsis if the string has been shortened. Words are not split up
+// if possible.
+func truncate(length int, s string) string {
+	if length < 0 {
+		length = 0
+	}
+	if utf8.RuneCountInString(s) <= length {
+		return s
+	}
+
+	runes := []rune(s)
+	truncated := string(runes[:length])
+
+	// Cut off the last word unless it ends right at the given length.
+	if !unicode.IsSpace(runes[length]) {
+		if i := strings.LastIndexFunc(truncated, unicode.IsSpace); i > 0 {
+			truncated = truncated[:i]
+		}
+	}
+
+	return strings.TrimSpace(truncated) + "…"
+}
+
+// plainify removes all HTML tags from a string, for example from the
+// rendered content of a page.
+func plainify(s interface{}) string {
+	return htmlTags.ReplaceAllString(fmt.Sprint(s), "")
+}
+
+// jsonify encodes a value as JSON.
+func jsonify(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func add(a, b int) int {
+	return a + b
+}
+
+func sub(a, b int) int {
+	return a - b
+}
+
+func mul(a, b int) int {
+	return a * b
+}
+
+func div(a, b int) (int, error) {
+	if b == 0 {
+		return 0, ErrDivisionByZero
+	}
+	return a / b, nil
+}
+
+func mod(a, b int) (int, error) {
+	if b == 0 {
+		return 0, ErrDivisionByZero
+	}
+	return a % b, nil
+}
+
+// safeHTML marks a string as safe HTML that won't be escaped.
+func safeHTML(s interface{}) htmltemplate.HTML {
+	return htmltemplate.HTML(fmt.Sprint(s))
+}
+
+// markdownify renders a Markdown string as HTML. If the result consists
+// of a single paragraph, the enclosing <p> tags are removed so that the
+// string can be used inline, for example in headings.
+func markdownify(s string) (htmltemplate.HTML, error) {
+	var buf bytes.Buffer
+
+	if err := markdown.Convert([]byte(s), &buf); err != nil {
+		return "", err
+	}
+
+	html := strings.TrimSpace(buf.String())
+
+	if strings.HasPrefix(html, "<p>") && strings.HasSuffix(html, "</p>") &&
+		strings.Count(html, "<p>") == 1 {
+		html = strings.TrimSuffix(strings.TrimPrefix(html, "<p>"), "</p>")
+	}
+
+	return htmltemplate.HTML(html), nil
+}
+
+// first returns the first n items of a slice, for example
+// {{range first 5 .Pages}}.
+func first(n int, items interface{}) (interface{}, error) {
+	v, err := sliceValue(items)
+	if err != nil {
+		return nil, err
+	}
+
+	if n < 0 {
+		return nil, fmt.Errorf("invalid number of items %d", n)
+	}
+	if n > v.Len() {
+		n = v.Len()
+	}
+
+	return v.Slice(0, n).Interface(), nil
+}
+
+// last returns the last n items of a slice.
+func last(n int, items interface{}) (interface{}, error) {
+	v, err := sliceValue(items)
+	if err != nil {
+		return nil, err
+	}
+
+	if n < 0 {
+		return nil, fmt.Errorf("invalid number of items %d", n)
+	}
+	if n > v.Len() {
+		n = v.Len()
+	}
+
+	return v.Slice(v.Len()-n, v.Len()).Interface(), nil
+}
+
+// where returns all items of a slice whose field has the given value, for
+// example {{range where "Author" "Alice" .Pages}}. If the field is a
+// slice like Tags, the item is returned if the slice contains the value.
+func where(key string, value interface{}, items interface{}) (interface{}, error) {
+	v, err := sliceValue(items)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := reflect.MakeSlice(v.Type(), 0, v.Len())
+
+	for i := 0; i < v.Len(); i++ {
+		field, err := fieldValue(v.Index(i), key)
+		if err != nil {
+			return nil, err
+		}
+		if matches(field, value) {
+			filtered = reflect.Append(filtered, v.Index(i))
+		}
+	}
+
+	return filtered.Interface(), nil
+}
+
+// groupBy groups pages by the given field, for example
+// {{range groupBy "Date.Year" .Pages}}. The groups are ordered by the
+// first occurrence of their key, keeping the order of sorted pages.
+func groupBy(key string, pages []*model.Page) ([]PageGroup, error) {
+	groups := make([]PageGroup, 0)
+	indexes := make(map[interface{}]int)
+
+	for _, page := range pages {
+		field, err := fieldValue(reflect.ValueOf(page), key)
+		if err != nil {
+			return nil, err
+		}
+
+		groupKey := field
+		if field != nil && !reflect.TypeOf(field).Comparable() {
+			groupKey = fmt.Sprint(field)
+		}
+
+		i, exists := indexes[groupKey]
+		if !exists {
+			i = len(groups)
+			indexes[groupKey] = i
+			groups = append(groups, PageGroup{Key: groupKey})
+		}
+
+		groups[i].Pages = append(groups[i].Pages, page)
+	}
+
+	return groups, nil
+}
+
+// in reports whether a slice contains the given value or whether a string
+// contains the given substring.
+func in(items interface{}, value interface{}) (bool, error) {
+	if s, ok := items.(string); ok {
+		return strings.Contains(s, fmt.Sprint(value)), nil
+	}
+
+	v, err := sliceValue(items)
+	if err != nil {
+		return false, err
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if equal(v.Index(i).Interface(), value) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// sortBy returns a copy of the given pages sorted by the given field in
+// the given order, for example {{range sortBy "title" "asc" .Pages}}. The
+// order may be empty to use the field's default order.
+func sortBy(by, order string, pages []*model.Page) ([]*model.Page, error) {
+	sorted := make([]*model.Page, len(pages))
+	copy(sorted, pages)
+
+	if err := model.SortPages(sorted, by, order); err != nil {
+		return nil, err
+	}
+
+	return sorted, nil
+}
+
+// reverse returns a copy of the given pages in reverse order.
+func reverse(pages []*model.Page) []*model.Page {
+	reversed := make([]*model.Page, len(pages))
+	for i, p := range pages {
+		reversed[len(pages)-1-i] = p
+	}
+	return reversed
+}
+
+// sliceValue returns the reflection value of a slice.
+func sliceValue(items interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(items)
+
+	if v.Kind() != reflect.Slice {
+		return reflect.Value{}, fmt.Errorf("expected slice, got %T", items)
+	}
+
+	return v, nil
+}
+
+// fieldValue resolves a field path like Date.Year or Params.origin on the
+// given value. Each element of the path may be a struct field, a method
+// without arguments or a map key. A nil value along the path resolves to
+// nil.
+func fieldValue(v reflect.Value, key string) (interface{}, error) {
+	for _, name := range strings.Split(key, ".") {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return nil, nil
+			}
+			if method := v.MethodByName(name); method.IsValid() {
+				break
+			}
+			v = v.Elem()
+		}
+
+		if method := v.MethodByName(name); method.IsValid() {
+			if method.Type().NumIn() != 0 || method.Type().NumOut() == 0 {
+				return nil, fmt.Errorf("method %s cannot be used as field", name)
+			}
+			v = method.Call(nil)[0]
+			continue
+		}
+
+		switch v.Kind() {
+		case reflect.Struct:
+			field := v.FieldByName(name)
+			if !field.IsValid() {
+				return nil, fmt.Errorf("field %s not found in %s", name, v.Type())
+			}
+			v = field
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return nil, fmt.Errorf("cannot resolve key %s in %s", name, v.Type())
+			}
+			v = v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+			if !v.IsValid() {
+				return nil, nil
+			}
+		default:
+			return nil, fmt.Errorf("cannot resolve field %s in %s", name, v.Type())
+		}
+	}
+
+	if !v.CanInterface() {
+		return nil, fmt.Errorf("cannot access field %s", key)
+	}
+
+	return v.Interface(), nil
+}
+
+// matches reports whether a field has the given value. If the field is a
+// slice, matches reports whether the slice contains the value.
+func matches(field, value interface{}) bool {
+	if field == nil {
+		return value == nil
+	}
+
+	v := reflect.ValueOf(field)
+
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < v.Len(); i++ {
+			if equal(v.Index(i).Interface(), value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return equal(field, value)
+}
+
+// equal reports whether two values are equal. Numbers of different types
+// are compared by their value and values implementing fmt.Stringer, like
+// tags, are compared by their string representation.
+func equal(a, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+
+	if x, ok := number(a); ok {
+		if y, ok := number(b); ok {
+			return x == y
+		}
+	}
+
+	_, aStringer := a.(fmt.Stringer)
+	_, bStringer := b.(fmt.Stringer)
+
+	if aStringer || bStringer {
+		return fmt.Sprint(a) == fmt.Sprint(b)
+	}
+
+	return false
+}
+
+// number converts an integer or float into a float64.
+func number(val interface{}) (float64, bool) {
+	v := reflect.ValueOf(val)
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+
+	return 0, false
+}
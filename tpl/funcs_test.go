@@ -0,0 +1,181 @@
+package tpl
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/verless/verless/model"
+	"github.com/verless/verless/test"
+)
+
+// TestFuncs checks if the sort functions can be used in templates.
+func TestFuncs(t *testing.T) {
+	pages := []*model.Page{
+		{Href: "/b", Title: "B", Weight: 1},
+		{Href: "/a", Title: "A", Weight: 2},
+		{Href: "/c", Title: "C", Weight: 3},
+	}
+
+	tests := map[string]struct {
+		template      string
+		expected      string
+		expectedError bool
+	}{
+		"sortBy": {
+			template: `{{range sortBy "title" "" .}}{{.Href}}{{end}}`,
+			expected: "/a/b/c",
+		},
+		"sortBy pipeline": {
+			template: `{{range . | sortBy "weight" "desc"}}{{.Href}}{{end}}`,
+			expected: "/c/a/b",
+		},
+		"reverse": {
+			template: `{{range reverse .}}{{.Href}}{{end}}`,
+			expected: "/c/a/b",
+		},
+		"invalid field": {
+			template:      `{{range sortBy "author" "" .}}{{.Href}}{{end}}`,
+			expectedError: true,
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		tpl, err := template.New(name).Funcs(funcs).Parse(testCase.template)
+		test.Ok(t, err)
+
+		var out strings.Builder
+		err = tpl.Execute(&out, pages)
+
+		if testCase.expectedError {
+			test.Assert(t, err != nil, "invalid sort options should return an error")
+			continue
+		}
+		test.Ok(t, err)
+		test.Equals(t, testCase.expected, out.String())
+
+		// The original pages must not be sorted.
+		test.Equals(t, "/b", pages[0].Href)
+	}
+}
+
+// TestFuncs_library checks if the functions of the standard function
+// library can be used in templates.
+func TestFuncs_library(t *testing.T) {
+	data := map[string]interface{}{
+		"Date": time.Date(2021, 1, 8, 0, 0, 0, 0, time.UTC),
+		"Text": "Making coffee is an art",
+		"Pages": []*model.Page{
+			{Href: "/a", Author: "Alice", Date: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), Tags: []model.Tag{{Name: "coffee"}}},
+			{Href: "/b", Author: "Bob", Date: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Weight: 2},
+			{Href: "/c", Author: "Alice", Date: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC), Params: map[string]interface{}{"origin": "Kenya"}},
+		},
+	}
+
+	tests := map[string]struct {
+		template      string
+		expected      string
+		expectedError bool
+	}{
+		"dateFormat": {
+			template: `{{dateFormat "Jan 2 2006" .Date}}`,
+			expected: "Jan 8 2021",
+		},
+		"dateFormat with strings": {
+			template: `{{dateFormat "Jan 2 2006" "2020-01-02"}} {{dateFormat "Jan 2 2006" "2020-01-02T15:04:05Z"}}`,
+			expected: "Jan 2 2020 Jan 2 2020",
+		},
+		"dateFormat with invalid string": {
+			template:      `{{dateFormat "Jan 2 2006" "January 2nd"}}`,
+			expectedError: true,
+		},
+		"absURL": {
+			template: `{{absURL "/blog/coffee"}} {{absURL "blog/"}}`,
+			expected: "https://example.com/blog/coffee https://example.com/blog/",
+		},
+		"relURL": {
+			template: `{{relURL "blog/coffee"}}`,
+			expected: "/blog/coffee",
+		},
+		"string functions": {
+			template: `{{.Text | upper}} {{.Text | replace "coffee" "tea"}} {{slugify .Text}}`,
+			expected: "MAKING COFFEE IS AN ART Making tea is an art making-coffee-is-an-art",
+		},
+		"truncate": {
+			template: `{{truncate 13 .Text}}`,
+			expected: "Making coffee…",
+		},
+		"plainify": {
+			template: `{{plainify "<p>Espresso <em>doppio</em></p>"}}`,
+			expected: "Espresso doppio",
+		},
+		"jsonify": {
+			template: `{{jsonify (split "a,b" ",")}}`,
+			expected: `["a","b"]`,
+		},
+		"math functions": {
+			template: `{{add 1 2}} {{sub 1 2}} {{mul 2 3}} {{div 7 2}} {{mod 7 2}}`,
+			expected: "3 -1 6 3 1",
+		},
+		"division by zero": {
+			template:      `{{div 1 0}}`,
+			expectedError: true,
+		},
+		"markdownify": {
+			template: `{{markdownify "Espresso *doppio*"}}`,
+			expected: "Espresso <em>doppio</em>",
+		},
+		"first and last": {
+			template: `{{range first 2 .Pages}}{{.Href}}{{end}} {{range last 5 .Pages}}{{.Href}}{{end}}`,
+			expected: "/a/b /a/b/c",
+		},
+		"where": {
+			template: `{{range where "Author" "Alice" .Pages}}{{.Href}}{{end}}`,
+			expected: "/a/c",
+		},
+		"where with slice field": {
+			template: `{{range where "Tags" "coffee" .Pages}}{{.Href}}{{end}}`,
+			expected: "/a",
+		},
+		"where with params": {
+			template: `{{range where "Params.origin" "Kenya" .Pages}}{{.Href}}{{end}}`,
+			expected: "/c",
+		},
+		"where with number": {
+			template: `{{range where "Weight" 2 .Pages}}{{.Href}}{{end}}`,
+			expected: "/b",
+		},
+		"where with unknown field": {
+			template:      `{{range where "Origin" "Kenya" .Pages}}{{.Href}}{{end}}`,
+			expectedError: true,
+		},
+		"groupBy": {
+			template: `{{range groupBy "Date.Year" .Pages}}{{.Key}}:{{range .Pages}}{{.Href}}{{end}} {{end}}`,
+			expected: "2021:/a 2020:/b/c ",
+		},
+		"in": {
+			template: `{{in (split "a,b" ",") "b"}} {{in .Text "tea"}}`,
+			expected: "true false",
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		tpl, err := template.New(name).Funcs(Funcs("https://example.com/")).Parse(testCase.template)
+		test.Ok(t, err)
+
+		var out strings.Builder
+		err = tpl.Execute(&out, data)
+
+		if testCase.expectedError {
+			test.Assert(t, err != nil, "invalid function calls should return an error")
+			continue
+		}
+		test.Ok(t, err)
+		test.Equals(t, testCase.expected, out.String())
+	}
+}
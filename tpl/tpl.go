@@ -1,66 +1,145 @@
-// Package tpl provides a simple template registry and corresponding
-// function for registering and loading templates from that registry.
+// Package tpl provides functions for parsing templates along with their
+// base template and partials, and the functions available in templates.
 package tpl
 
 import (
-	"errors"
 	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"text/template"
+	"text/template/parse"
+
+	"github.com/verless/verless/theme"
 )
 
-var (
-	// templates stores all successfully rendered template instances.
-	templates map[string]*template.Template
+// Template represents a parsed template that can be executed.
+type Template interface {
+	Execute(wr io.Writer, data interface{}) error
+}
 
-	// ErrAlreadyRegistered is returned when a template with a given
-	// key has already been registered.
-	ErrAlreadyRegistered = errors.New("template has already been registered")
-)
+// Options configures how a template is parsed.
+type Options struct {
+	// Dir is the template directory containing the partials directory
+	// and the base template. Defaults to the directory of the template.
+	Dir string
+	// Compat indicates whether the template should be parsed using
+	// text/template instead of html/template.
+	Compat bool
+	// Funcs are the functions available in the template. Defaults to
+	// the built-in functions returned by Funcs without a base URL.
+	Funcs template.FuncMap
+}
+
+// Parse parses a template file and returns the template instance.
+//
+// All templates in the partials directory are parsed along with the
+// template, so that they can be included using {{template "partials/
+// head.html" .}}. If the template only consists of definitions like
+// {{define "main"}}, it fills the blocks of the base template, which
+// then is used for rendering.
+//
+// Templates are parsed using html/template, which escapes all values
+// depending on their context. In compatibility mode, text/template is
+// used instead, rendering all values as they are.
+func Parse(path string, opts Options) (Template, error) {
+	if opts.Dir == "" {
+		opts.Dir = filepath.Dir(path)
+	}
 
-// Register parses a template file and registers the instance under
-// the given key. If a template with the key has already registered,
-// Register will return an error unless the registration is forced.
-func Register(key string, path string, force bool) (*template.Template, error) {
-	if templates == nil {
-		templates = make(map[string]*template.Template)
+	if opts.Funcs == nil {
+		opts.Funcs = Funcs("")
 	}
 
-	if !force {
-		if _, exists := templates[key]; exists {
-			return nil, ErrAlreadyRegistered
+	files, err := Files(opts.Dir, path)
+	if err != nil {
+		return nil, err
+	}
+
+	root := template.New("").Funcs(opts.Funcs)
+
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := root.New(templateName(opts.Dir, file)).Parse(string(src)); err != nil {
+			return nil, err
 		}
 	}
 
-	tpl, err := template.ParseFiles(path)
+	name := templateName(opts.Dir, path)
+
+	// Render the base template if the template only fills its blocks.
+	if files[0] != path && isEmpty(root.Lookup(name)) {
+		name = templateName(opts.Dir, files[0])
+	}
+
+	return lookup(root, name, opts)
+}
+
+// Files returns all template files that have to be parsed for the given
+// template inside the template directory dir: The base template if it
+// exists, all partials and the template itself.
+func Files(dir, path string) ([]string, error) {
+	files := make([]string, 0)
+
+	base := filepath.Join(dir, theme.BaseTemplate)
+	if _, err := os.Stat(base); err == nil && base != path {
+		files = append(files, base)
+	}
+
+	partials, err := filepath.Glob(filepath.Join(dir, theme.PartialsDir, "*.html"))
 	if err != nil {
 		return nil, err
 	}
 
-	templates[key] = tpl
+	files = append(files, partials...)
+	files = append(files, path)
 
-	return templates[key], nil
+	return files, nil
 }
 
-// Get returns the template registered under the given key.
-//
-// The template must be registered using Register first. If it hasn't
-// been registered successfully before, Get will return an error. Use
-// IsRegistered if you're unsure if the template exists.
-func Get(key string) (*template.Template, error) {
-	if templates == nil {
-		templates = make(map[string]*template.Template)
+// lookup returns the template with the given name from the set of parsed
+// templates. Unless opts.Compat is true, all templates are converted into
+// HTML templates.
+func lookup(root *template.Template, name string, opts Options) (Template, error) {
+	if root.Lookup(name) == nil {
+		return nil, fmt.Errorf("template %s not found", name)
+	}
+
+	if opts.Compat {
+		return root.Lookup(name), nil
 	}
 
-	if _, exists := templates[key]; !exists {
-		return nil, fmt.Errorf("template %s has not been registered", key)
+	htmlRoot := htmltemplate.New("").Funcs(htmltemplate.FuncMap(opts.Funcs))
+
+	for _, t := range root.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+		if _, err := htmlRoot.AddParseTree(t.Name(), t.Tree); err != nil {
+			return nil, err
+		}
 	}
 
-	return templates[key], nil
+	return htmlRoot.Lookup(name), nil
+}
+
+// templateName returns the name of a template file, which is its path
+// relative to the template directory, like partials/head.html.
+func templateName(dir, path string) string {
+	name, err := filepath.Rel(dir, path)
+	if err != nil {
+		return filepath.Base(path)
+	}
+	return filepath.ToSlash(name)
 }
 
-// IsRegistered indicates whether a template with the given key has
-// been registered successfully using Register.
-func IsRegistered(key string) bool {
-	_, exists := templates[key]
-	return exists
+// isEmpty determines whether a template is empty or only consists of
+// definitions.
+func isEmpty(t *template.Template) bool {
+	return t == nil || t.Tree == nil || parse.IsEmptyTree(t.Tree.Root)
 }
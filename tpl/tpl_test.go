@@ -1,73 +1,139 @@
 package tpl
 
 import (
+	"io/ioutil"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
-	"text/template"
 
+	"github.com/verless/verless/model"
 	"github.com/verless/verless/test"
 	"github.com/verless/verless/theme"
 )
 
 const (
 	projectPath = "../example"
-	testKey     = "test key"
-	invalidKey  = "invalid key"
 )
 
-// TestRegister checks if the Register function register all templates
-// correctly and if it doesn't allow overwriting a template by default.
-func TestRegister(t *testing.T) {
-	// Don't use a map as the execution order is important here.
-	tests := []struct {
-		testName      string
-		force         bool
-		expectedError error
-		key           string
+// TestParse checks if the Parse function parses existing templates and
+// returns an error for missing templates.
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		path          string
+		expectedError bool
 	}{
-		{
-			testName: "first template",
-			key:      "test key",
+		"page template": {
+			path: filepath.Join(theme.TemplatePath(projectPath, theme.Default), theme.PageTemplate),
 		},
-		{
-			testName: "second template",
-			key:      "test key2",
+		"missing template": {
+			path:          filepath.Join(theme.TemplatePath(projectPath, theme.Default), "missing.html"),
+			expectedError: true,
 		},
-		{
-			testName:      "same template again",
-			key:           "test key2",
-			expectedError: ErrAlreadyRegistered,
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		_, err := Parse(testCase.path, Options{})
+
+		if testCase.expectedError {
+			test.Assert(t, err != nil, "missing templates should return an error")
+			continue
+		}
+		test.Ok(t, err)
+	}
+}
+
+// TestParse_escaping checks if values are escaped by default and if
+// they are rendered as they are in compatibility mode.
+func TestParse_escaping(t *testing.T) {
+	dir, err := ioutil.TempDir("", "verless-tpl")
+	test.Ok(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "page.html")
+	test.Ok(t, ioutil.WriteFile(path, []byte(`<h1>{{.Title}}</h1>{{.Content}}`), 0644))
+
+	page := model.Page{
+		Title:   "Coffee & <script>alert(1)</script>",
+		Content: "<p>Espresso</p>",
+	}
+
+	tests := map[string]struct {
+		compat   bool
+		expected string
+	}{
+		"html/template": {
+			expected: "<h1>Coffee &amp; &lt;script&gt;alert(1)&lt;/script&gt;</h1><p>Espresso</p>",
 		},
-		{
-			testName: "same template again with force true",
-			key:      "test key2",
-			force:    true,
+		"compatibility mode": {
+			compat:   true,
+			expected: "<h1>Coffee & <script>alert(1)</script></h1><p>Espresso</p>",
 		},
 	}
 
-	for _, testCase := range tests {
-		t.Logf("Testing '%s'", testCase.testName)
-		pageTplPath := filepath.Join(theme.TemplatePath(projectPath, theme.Default), theme.PageTemplate)
+	for name, testCase := range tests {
+		t.Log(name)
 
-		_, err := Register(testCase.key, pageTplPath, testCase.force)
-		test.ExpectedError(t, testCase.expectedError, err)
+		tpl, err := Parse(path, Options{Compat: testCase.compat})
+		test.Ok(t, err)
+
+		var out strings.Builder
+		test.Ok(t, tpl.Execute(&out, page))
+		test.Equals(t, testCase.expected, out.String())
 	}
 }
 
-// TestGet checks if the Get function correctly returns a registered
-// template.
-func TestGet(t *testing.T) {
-	if templates == nil {
-		templates = make(map[string]*template.Template)
+// TestParse_partials checks if partials can be included by templates
+// and if templates only consisting of definitions fill the blocks of the
+// base template.
+func TestParse_partials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "verless-tpl")
+	test.Ok(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	files := map[string]string{
+		theme.BaseTemplate: `<html>{{template "partials/head.html" .}}{{block "main" .}}Default{{end}}</html>`,
+		filepath.Join(theme.PartialsDir, "head.html"): `<title>{{.Title}}</title>`,
+		theme.PageTemplate:     `{{define "main"}}<h1>{{.Title}}</h1>{{end}}`,
+		theme.ListPageTemplate: `<ul>{{template "partials/head.html" .}}</ul>`,
 	}
 
-	templates[testKey] = &template.Template{}
+	for file, content := range files {
+		path := filepath.Join(dir, file)
+		test.Ok(t, os.MkdirAll(filepath.Dir(path), 0755))
+		test.Ok(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
 
-	tpl, err := Get(testKey)
-	test.Ok(t, err)
+	tests := map[string]struct {
+		template string
+		compat   bool
+		expected string
+	}{
+		"page filling the base template": {
+			template: theme.PageTemplate,
+			expected: "<html><title>Coffee &amp; Milk</title><h1>Coffee &amp; Milk</h1></html>",
+		},
+		"page in compatibility mode": {
+			template: theme.PageTemplate,
+			compat:   true,
+			expected: "<html><title>Coffee & Milk</title><h1>Coffee & Milk</h1></html>",
+		},
+		"standalone list page": {
+			template: theme.ListPageTemplate,
+			expected: "<ul><title>Coffee &amp; Milk</title></ul>",
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
 
-	test.Assert(t, tpl == templates[testKey], "template has to be in map")
+		tpl, err := Parse(filepath.Join(dir, testCase.template), Options{Dir: dir, Compat: testCase.compat})
+		test.Ok(t, err)
 
-	_, err = Get(invalidKey)
-	test.Assert(t, err != nil, "template key is invalid")
+		var out strings.Builder
+		test.Ok(t, tpl.Execute(&out, model.Page{Title: "Coffee & Milk"}))
+		test.Equals(t, testCase.expected, out.String())
+	}
 }
@@ -0,0 +1,58 @@
+package writer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var (
+	// templateLocationExpr matches the template name and line number inside
+	// errors returned when parsing or executing a template, for example
+	// `template: page.html:12:5: executing "page.html" at <.Foo>` or
+	// `html/template:page.html:12:5: ...`.
+	templateLocationExpr = regexp.MustCompile(`template: ?([^:]+):(\d+)`)
+)
+
+// TemplateError represents an error that arises when loading or executing
+// a template.
+type TemplateError struct {
+	// Template is the name of the template, like page.html.
+	Template string
+	// Path is the file path of the template that caused the error. This
+	// may also be the base template or a partial.
+	Path string
+	// Line is the line inside the file that caused the error. It is 0 if
+	// the line is unknown.
+	Line int
+	// Output is the output file that has been rendered, if any.
+	Output string
+	// Err is the actual error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *TemplateError) Error() string {
+	if e.Output == "" {
+		return fmt.Sprintf("template %s: %s", e.Template, e.Err)
+	}
+	return fmt.Sprintf("rendering %s using template %s: %s", e.Output, e.Template, e.Err)
+}
+
+// Unwrap returns the actual error.
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// templateLocation extracts the template name, like partials/head.html,
+// and the line number from a template error. If the error doesn't contain
+// a line number, templateLocation returns an empty name and 0.
+func templateLocation(err error) (string, int) {
+	matches := templateLocationExpr.FindStringSubmatch(err.Error())
+	if len(matches) < 3 {
+		return "", 0
+	}
+
+	line, _ := strconv.Atoi(matches[2])
+	return matches[1], line
+}
@@ -0,0 +1,155 @@
+package writer
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/verless/verless/cache"
+	"github.com/verless/verless/model"
+)
+
+const (
+	// RedirectsNetlify is the format of the _redirects file supported by
+	// Netlify and Cloudflare Pages.
+	RedirectsNetlify string = "netlify"
+	// RedirectsNginx is the format of a file that can be included into
+	// an nginx map block.
+	RedirectsNginx string = "nginx"
+)
+
+// redirectFiles contains the filenames and line formats of all
+// supported redirect file formats.
+var redirectFiles = map[string]struct {
+	filename string
+	line     func(from, to string) string
+}{
+	RedirectsNetlify: {
+		filename: "_redirects",
+		line: func(from, to string) string {
+			return fmt.Sprintf("%s %s 301\n", from, to)
+		},
+	},
+	RedirectsNginx: {
+		filename: "redirects.map",
+		line: func(from, to string) string {
+			// nginx doesn't normalize trailing slashes, so both forms
+			// have to be redirected.
+			if from == "/" {
+				return fmt.Sprintf("%s %s;\n", from, to)
+			}
+			return fmt.Sprintf("%s %s;\n%s/ %s;\n", from, to, from, to)
+		},
+	},
+}
+
+// redirectStub is a page that redirects to another page without any
+// server-side support. It is formatted with the target URL and the
+// attributes of the html element, like the language.
+const redirectStub = `<!DOCTYPE html>
+<html%[2]s>
+    <head>
+        <meta charset="utf-8" />
+        <title>%[1]s</title>
+        <link rel="canonical" href="%[1]s" />
+        <meta name="robots" content="noindex" />
+        <meta http-equiv="refresh" content="0; url=%[1]s" />
+    </head>
+    <body>
+        <a href="%[1]s">%[1]s</a>
+    </body>
+</html>
+`
+
+// writeRedirectStubs writes a redirect stub for each redirect of the
+// site.
+func (w *writer) writeRedirectStubs() error {
+	for _, redirect := range w.site.Redirects {
+		if err := w.writeRedirectStub(redirect); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeRedirectFiles writes the given redirects into redirect files in
+// all configured formats.
+func (w *writer) writeRedirectFiles(redirects []model.Redirect) error {
+	for _, format := range w.ctx.RedirectFormats {
+		file, ok := redirectFiles[format]
+		if !ok {
+			return fmt.Errorf("invalid redirect format %s: expected one of %s", format, strings.Join(redirectFormats(), ", "))
+		}
+
+		var buf bytes.Buffer
+		for _, redirect := range redirects {
+			buf.WriteString(file.line(redirect.From, redirect.To))
+		}
+
+		path := filepath.Join(w.ctx.OutputDir, file.filename)
+		key := cache.Hash(buf.Bytes())
+
+		if w.isFresh(path, key) {
+			w.storeOutput(path, key, true)
+			continue
+		}
+
+		if err := afero.WriteFile(w.ctx.Fs, path, buf.Bytes(), 0644); err != nil {
+			return err
+		}
+
+		w.storeOutput(path, key, false)
+	}
+
+	return nil
+}
+
+// writeRedirectStub writes an HTML page at the redirect's alias that
+// immediately redirects to the redirect's target.
+func (w *writer) writeRedirectStub(redirect model.Redirect) error {
+	path := filepath.Join(w.ctx.OutputDir, redirect.From, indexFile)
+	target := strings.TrimSuffix(w.site.Meta.Base, "/") + redirect.To
+
+	// Single-language websites don't have a language code, in which case
+	// the language of the stub is left unspecified.
+	var attrs string
+	if w.site.Language.Code != "" {
+		attrs = fmt.Sprintf(` lang="%s"`, html.EscapeString(w.site.Language.Code))
+	}
+
+	key := cache.Hash([]byte(target), []byte(attrs))
+
+	if w.isFresh(path, key) {
+		w.storeOutput(path, key, true)
+		return nil
+	}
+
+	if err := w.ctx.Fs.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	stub := fmt.Sprintf(redirectStub, html.EscapeString(target), attrs)
+
+	if err := afero.WriteFile(w.ctx.Fs, path, []byte(stub), 0644); err != nil {
+		return err
+	}
+
+	w.storeOutput(path, key, false)
+
+	return nil
+}
+
+// redirectFormats returns the names of all supported redirect formats.
+func redirectFormats() []string {
+	formats := make([]string, 0, len(redirectFiles))
+	for format := range redirectFiles {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
@@ -10,6 +10,13 @@ type page struct {
 	Nav    *model.Nav
 	Page   *model.Page
 	Footer *model.Footer
+	Data   map[string]interface{}
+	// Language is the language of the page. Languages contains all
+	// languages of a multilingual website and I18n the translated
+	// strings of the page's language.
+	Language  *model.Language
+	Languages []model.Language
+	I18n      map[string]interface{}
 }
 
 // listPage is a wrapper for ListPage-related templates.
@@ -18,4 +25,13 @@ type listPage struct {
 	Nav  *model.Nav
 	*model.ListPage
 	Footer *model.Footer
+	Data   map[string]interface{}
+	// Paginator represents the current page of the list page.
+	Paginator *model.Paginator
+	// Language is the language of the list page. Languages contains all
+	// languages of a multilingual website and I18n the translated
+	// strings of the list page's language.
+	Language  *model.Language
+	Languages []model.Language
+	I18n      map[string]interface{}
 }
@@ -3,13 +3,25 @@
 package writer
 
 import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"text/template"
 
 	"github.com/spf13/afero"
+	"github.com/verless/verless/cache"
 	"github.com/verless/verless/config"
 	"github.com/verless/verless/fs"
+	"github.com/verless/verless/graph"
 	"github.com/verless/verless/model"
+	"github.com/verless/verless/out"
+	"github.com/verless/verless/out/style"
 	"github.com/verless/verless/theme"
 	"github.com/verless/verless/tpl"
 	"github.com/verless/verless/tree"
@@ -17,6 +29,12 @@ import (
 
 const (
 	indexFile string = "index.html"
+	// pageSizeKey is the front matter key of custom list pages for
+	// overriding the page size.
+	pageSizeKey string = "PageSize"
+	// templateKey is the front matter key for overriding the template
+	// of a page or a custom list page.
+	templateKey string = "Template"
 )
 
 type Context struct {
@@ -25,6 +43,30 @@ type Context struct {
 	OutputDir          string
 	Theme              string
 	RecompileTemplates bool
+	// CompatTemplates indicates whether templates should be rendered
+	// using text/template instead of html/template, which doesn't escape
+	// any values.
+	CompatTemplates bool
+	// DebugTemplates indicates whether the template used for each output
+	// file should be printed.
+	DebugTemplates bool
+	// PageSize is the maximum number of pages per list page. List pages
+	// with more pages are paginated. If PageSize is 0, all pages are put
+	// onto a single list page.
+	PageSize int
+	// RedirectFormats contains the formats of the redirect files that
+	// are written in addition to the redirect stubs for page aliases.
+	RedirectFormats []string
+	// Cache is the build cache used for skipping pages that didn't
+	// change. If Cache is nil, all pages will be rendered.
+	Cache *cache.Cache
+	// Graph is the dependency graph where all outputs are registered
+	// along with the files they depend on. Graph may be nil.
+	Graph *graph.Graph
+	// Funcs contains additional template functions, like the ones
+	// provided by plugins. They may replace built-in functions. As
+	// Funcs is read when writing, functions may be added after New.
+	Funcs map[string]interface{}
 }
 
 // New creates a new writer that renders the site model in the given
@@ -34,34 +76,132 @@ func New(ctx Context) *writer {
 		ctx.Theme = theme.Default
 	}
 
-	w := writer{ctx: ctx}
+	w := writer{
+		ctx:            ctx,
+		templateHashes: make(map[string]string),
+		templateFiles:  make(map[string]bool),
+		templates:      make(map[string]tpl.Template),
+	}
 
 	return &w
 }
 
 type writer struct {
-	site model.Site
-	ctx  Context
+	site           model.Site
+	ctx            Context
+	templateHashes map[string]string
+	templateFiles  map[string]bool
+	// templates contains all templates parsed for the current build,
+	// keyed by language, as functions like absURL depend on it.
+	templates map[string]tpl.Template
+	dataHash  string
+	// changed contains the changed files of a partial build. If changed
+	// is nil, all outputs are considered as affected.
+	changed map[string]bool
+	// affected contains all outputs of a partial build that depend on
+	// one of the changed files.
+	affected map[string]bool
 }
 
-// Write renders the entire site model to the writer's filesystem.
+// Write renders the entire site model to the writer's filesystem. For
+// multilingual websites, Write renders the sites of all languages.
 //
 // Basically, it creates a directory for each page and renders the
 // page using its respective template. It also copies all assets.
-func (w *writer) Write(site model.Site) error {
-	if err := fs.Rmdir(w.ctx.Fs, w.ctx.OutputDir); err != nil {
+//
+// If the state of a previous build is available in the build cache, pages
+// that are still fresh won't be rendered again. Otherwise, the output
+// directory is cleaned up first.
+func (w *writer) Write(sites ...model.Site) error {
+	if w.changed == nil && (w.ctx.Cache == nil || !w.ctx.Cache.Loaded()) {
+		if err := fs.Rmdir(w.ctx.Fs, w.ctx.OutputDir); err != nil {
+			return err
+		}
+	}
+
+	// The template functions may have changed since the last build.
+	w.templates = make(map[string]tpl.Template)
+
+	redirects := make([]model.Redirect, 0)
+
+	for _, site := range sites {
+		if err := w.writeSite(site); err != nil {
+			return err
+		}
+		redirects = append(redirects, site.Redirects...)
+	}
+
+	sort.Slice(redirects, func(i, j int) bool {
+		return redirects[i].From < redirects[j].From
+	})
+
+	if err := w.writeRedirectFiles(redirects); err != nil {
+		return err
+	}
+
+	if err := w.copyDirs(); err != nil {
 		return err
 	}
 
+	return nil
+}
+
+// WriteChanged renders the site models like Write, but only renders the
+// outputs that depend on one of the changed files and outputs that don't
+// exist yet. All other outputs are reused as they are.
+func (w *writer) WriteChanged(changed map[string]bool, sites ...model.Site) error {
+	if w.ctx.Graph == nil || w.ctx.Cache == nil {
+		return w.Write(sites...)
+	}
+
+	w.changed = changed
+	w.affected = make(map[string]bool)
+
+	defer func() {
+		w.changed = nil
+		w.affected = nil
+	}()
+
+	// The changed files may be templates, so their hashes and existence
+	// have to be determined again.
+	w.templateHashes = make(map[string]string)
+	w.templateFiles = make(map[string]bool)
+
+	// Outputs that depended on a changed file in the previous build are
+	// affected even if they don't depend on it anymore.
+	for file := range changed {
+		for _, output := range w.ctx.Graph.Dependents(file) {
+			w.affected[output] = true
+		}
+	}
+
+	return w.Write(sites...)
+}
+
+// writeSite renders all pages and list pages of a site as well as the
+// redirect stubs for all page aliases.
+func (w *writer) writeSite(site model.Site) error {
 	w.site = site
 
-	err := tree.Walk(w.site.Root, func(_ string, node tree.Node) error {
+	// All pages have to be rendered again if the data files or the
+	// translated strings changed.
+	dataHash, err := cache.HashValues(w.site.Data, w.site.I18n, w.site.Languages)
+	if err != nil {
+		return err
+	}
+	w.dataHash = dataHash
+
+	err = tree.Walk(w.site.Root, func(_ string, node tree.Node) error {
 		for _, p := range node.(*model.Node).Pages {
-			if err := w.writePage(p.Route, page{
-				Meta:   &w.site.Meta,
-				Nav:    &w.site.Nav,
-				Page:   &p,
-				Footer: &w.site.Footer,
+			if err := w.writePage(page{
+				Meta:      &w.site.Meta,
+				Nav:       &w.site.Nav,
+				Page:      &p,
+				Footer:    &w.site.Footer,
+				Data:      w.site.Data,
+				Language:  &w.site.Language,
+				Languages: w.site.Languages,
+				I18n:      w.site.I18n,
 			}); err != nil {
 				return err
 			}
@@ -74,10 +214,14 @@ func (w *writer) Write(site model.Site) error {
 		}
 
 		return w.writeListPage(lp.Route, listPage{
-			Meta:     &w.site.Meta,
-			Nav:      &w.site.Nav,
-			ListPage: &lp,
-			Footer:   &w.site.Footer,
+			Meta:      &w.site.Meta,
+			Nav:       &w.site.Nav,
+			ListPage:  &lp,
+			Footer:    &w.site.Footer,
+			Data:      w.site.Data,
+			Language:  &w.site.Language,
+			Languages: w.site.Languages,
+			I18n:      w.site.I18n,
 		})
 	}, -1)
 
@@ -85,75 +229,513 @@ func (w *writer) Write(site model.Site) error {
 		return err
 	}
 
-	if err := w.copyDirs(); err != nil {
-		return err
-	}
-
-	return nil
+	return w.writeRedirectStubs()
 }
 
 // writePage renders a single page by applying the associated template
-// and writing the file inside the output directory.
-func (w *writer) writePage(route string, page page) error {
-	path := filepath.Join(w.ctx.OutputDir, route, page.Page.ID)
-
-	if err := w.ctx.Fs.MkdirAll(path, 0700); err != nil {
+// and writing the file inside the output directory. The output path is
+// determined by the page's Href, which may differ from its route if a
+// permalink pattern applies.
+func (w *writer) writePage(page page) error {
+	path := filepath.Join(w.ctx.OutputDir, page.Page.Href, indexFile)
+	tplName, err := w.templateName(page.Page, section(page.Page.Route), theme.PageTemplate)
+	if err != nil {
 		return err
 	}
 
-	file, err := w.ctx.Fs.Create(filepath.Join(path, indexFile))
+	tplHash, err := w.templateHash(tplName)
 	if err != nil {
 		return err
 	}
 
-	pageTpl, err := w.loadTemplate(page.Page.Type, theme.PageTemplate)
-	if err != nil {
+	// The page has to be rendered again if the page itself, one of its
+	// related pages, one of its translations or the list of its resources
+	// has changed.
+	key := []string{tplHash, w.dataHash, page.Page.Href, page.Page.SourceHash()}
+	sources := []string{page.Page.SourcePath()}
+
+	for _, related := range page.Page.Related {
+		key = append(key, related.Href, related.SourceHash())
+		sources = append(sources, related.SourcePath())
+	}
+
+	for _, translation := range page.Page.Translations {
+		key = append(key, translation.Href, translation.SourceHash())
+		sources = append(sources, translation.SourcePath())
+	}
+
+	for _, resource := range page.Page.Resources {
+		key = append(key, resource.Href)
+	}
+
+	w.addDependencies(path, tplName, sources)
+
+	if err := w.copyResources(filepath.Dir(path), page.Page.Resources); err != nil {
 		return err
 	}
 
-	return pageTpl.Execute(file, &page)
+	return w.render(path, key, tplName, &page)
+}
+
+// copyResources copies the resources of a page bundle into the given
+// directory, right next to the rendered page. Resources that didn't
+// change since the previous build aren't copied again.
+func (w *writer) copyResources(dir string, resources []model.Resource) error {
+	for _, resource := range resources {
+		dest := filepath.Join(dir, resource.Name)
+
+		// Register the resource as static file, so that it can be copied
+		// again on its own once it changes.
+		if w.ctx.Graph != nil {
+			w.ctx.Graph.Add(graph.Static, resource.SourcePath(), dest)
+		}
+
+		data, err := ioutil.ReadFile(resource.SourcePath())
+		if err != nil {
+			return err
+		}
+
+		hash := cache.Hash(data)
+
+		if !w.isFresh(dest, hash) {
+			if err := w.ctx.Fs.MkdirAll(dir, 0700); err != nil {
+				return err
+			}
+			if err := afero.WriteFile(w.ctx.Fs, dest, data, 0644); err != nil {
+				return err
+			}
+		}
+
+		if w.ctx.Cache != nil {
+			w.ctx.Cache.StoreCopy(dest, hash)
+		}
+	}
+
+	return nil
 }
 
 // writeListPage does the same thing as writePage but for list pages.
+// If the list page contains more pages than the page size, it is split
+// into multiple pages like /blog, /blog/page/2 and so on.
 func (w *writer) writeListPage(route string, listPage listPage) error {
-	path := filepath.Join(w.ctx.OutputDir, route)
+	pageSize, err := w.pageSize(listPage.ListPage)
+	if err != nil {
+		return err
+	}
+
+	// Each chunk depends on all pages of the list page, because adding or
+	// removing a page shifts the pages of the following chunks.
+	sources := make([]string, 0, len(listPage.Pages))
+	for _, p := range listPage.Pages {
+		sources = append(sources, p.SourcePath())
+	}
+
+	for _, paginator := range model.Paginate(route, listPage.Pages, pageSize) {
+		paginator := paginator
+
+		lp := *listPage.ListPage
+		lp.Pages = paginator.Pages
+
+		current := listPage
+		current.ListPage = &lp
+		current.Paginator = &paginator
+
+		if err := w.writeListPageChunk(route, current, sources); err != nil {
+			return err
+		}
+	}
 
-	if err := w.ctx.Fs.MkdirAll(path, 0700); err != nil {
+	return nil
+}
+
+// writeListPageChunk renders a single page of a paginated list page that
+// depends on the given source files.
+func (w *writer) writeListPageChunk(route string, listPage listPage, sources []string) error {
+	path := filepath.Join(w.ctx.OutputDir, route, indexFile)
+	if number := listPage.Paginator.PageNumber; number > 1 {
+		path = filepath.Join(w.ctx.OutputDir, route, model.PaginationDir, strconv.Itoa(number), indexFile)
+	}
+
+	kinds := []string{theme.ListPageTemplate}
+	if listPage.Taxonomy != "" {
+		kinds = []string{theme.TaxonomyTemplate, theme.ListPageTemplate}
+	}
+
+	tplName, err := w.templateName(&listPage.Page, section(strings.TrimPrefix(route, w.site.Language.Href)), kinds...)
+	if err != nil {
 		return err
 	}
 
-	file, err := w.ctx.Fs.Create(filepath.Join(path, indexFile))
+	tplHash, err := w.templateHash(tplName)
 	if err != nil {
 		return err
 	}
 
-	listPageTpl, err := w.loadTemplate(listPage.Type, theme.ListPageTemplate)
+	// The list page has to be rendered again if the list page itself,
+	// one of its pages, the order of its pages or the number of pages
+	// has changed.
+	key := []string{
+		tplHash, w.dataHash, listPage.Route, listPage.Page.SourceHash(),
+		strconv.Itoa(listPage.Paginator.PageNumber), strconv.Itoa(listPage.Paginator.TotalPages),
+	}
+	sources = append([]string{listPage.Page.SourcePath()}, sources...)
+
+	for _, p := range listPage.Pages {
+		key = append(key, p.Href, p.SourceHash())
+	}
+
+	w.addDependencies(path, tplName, sources)
+
+	return w.render(path, key, tplName, &listPage)
+}
+
+// pageSize determines the page size for the given list page. A custom
+// list page may override the global page size using the PageSize key.
+func (w *writer) pageSize(listPage *model.ListPage) (int, error) {
+	val := listPage.Param(pageSizeKey)
+
+	switch size := val.(type) {
+	case nil:
+		return w.ctx.PageSize, nil
+	case int:
+		return size, nil
+	case int64:
+		return int(size), nil
+	case float64:
+		if size == float64(int(size)) {
+			return int(size), nil
+		}
+	}
+
+	return 0, fmt.Errorf("%s: invalid value for key %s: expected integer, got %v",
+		listPage.SourcePath(), pageSizeKey, val)
+}
+
+// render executes the template with the given name and writes the result
+// to the given file. If the file is still fresh according to the build
+// cache, loading and executing the template is skipped.
+func (w *writer) render(file string, key []string, tplName string, data interface{}) error {
+	hash := cache.Hash(toBytes(key)...)
+
+	if w.ctx.DebugTemplates {
+		w.printTemplate(file, tplName)
+	}
+
+	if w.isUnaffected(file) {
+		w.ctx.Cache.ReuseOutput(file)
+		return nil
+	}
+
+	if w.isFresh(file, hash) {
+		w.storeOutput(file, hash, true)
+		return nil
+	}
+
+	tpl, err := w.loadTemplate(tplName)
 	if err != nil {
+		return w.templateError(tplName, file, err)
+	}
+
+	// Render into a buffer first, so that a failing template doesn't
+	// leave a truncated output file that would be considered fresh.
+	var buf bytes.Buffer
+
+	if err := tpl.Execute(&buf, data); err != nil {
+		if w.ctx.Cache != nil {
+			w.ctx.Cache.RemoveOutput(file)
+		}
+		return w.templateError(tplName, file, err)
+	}
+
+	if err := w.ctx.Fs.MkdirAll(filepath.Dir(file), 0700); err != nil {
 		return err
 	}
 
-	return listPageTpl.Execute(file, &listPage)
+	if err := afero.WriteFile(w.ctx.Fs, file, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	w.storeOutput(file, hash, false)
+
+	return nil
+}
+
+// printTemplate prints the template that is used for the given output
+// file.
+func (w *writer) printTemplate(file, tplName string) {
+	if rel, err := filepath.Rel(w.ctx.OutputDir, file); err == nil {
+		file = rel
+	}
+	out.T(style.None, "%s uses template %s", filepath.ToSlash(file), tplName)
+}
+
+// isFresh determines whether a file has been rendered with the same key
+// before and still exists in the output directory.
+func (w *writer) isFresh(file, key string) bool {
+	if w.ctx.Cache == nil || !w.ctx.Cache.IsFresh(file, key) {
+		return false
+	}
+	exists, err := afero.Exists(w.ctx.Fs, file)
+	return err == nil && exists
+}
+
+// storeOutput records an output file along with the key it has been
+// rendered with in the build cache, if caching is enabled.
+func (w *writer) storeOutput(file, key string, reused bool) {
+	if w.ctx.Cache != nil {
+		w.ctx.Cache.StoreOutput(file, key, reused)
+	}
 }
 
-// loadTemplate considers a page type and a default template, decides
-// which template to use and loads that template from the registry.
-func (w *writer) loadTemplate(t *model.Type, defaultTpl string) (*template.Template, error) {
-	var pageTpl string
+// isUnaffected determines whether a file doesn't have to be rendered in
+// a partial build because none of the files it depends on has changed.
+func (w *writer) isUnaffected(file string) bool {
+	if w.changed == nil || w.affected[file] {
+		return false
+	}
+	exists, err := afero.Exists(w.ctx.Fs, file)
+	return err == nil && exists
+}
+
+// Prune removes all output files that have been written by the previous
+// build but not by the current build, for example because the source
+// file has been deleted. Files outside of the output directory are never
+// removed.
+func (w *writer) Prune() error {
+	if w.ctx.Cache == nil {
+		return nil
+	}
+
+	for _, file := range w.ctx.Cache.Stale() {
+		// Never remove files that aren't inside the output directory,
+		// no matter what the cache contains.
+		if !isInside(w.ctx.OutputDir, file) {
+			continue
+		}
+
+		if err := w.ctx.Fs.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
 
-	switch {
-	case t != nil && t.Template != "":
-		pageTpl = t.Template
+		// Also remove the page directory if it is empty now.
+		dir := filepath.Dir(file)
+		if !isInside(w.ctx.OutputDir, dir) {
+			continue
+		}
+		if empty, err := afero.IsEmpty(w.ctx.Fs, dir); err == nil && empty {
+			_ = w.ctx.Fs.Remove(dir)
+		}
+	}
+
+	return nil
+}
+
+// isInside determines whether the given file is located inside dir. Both
+// paths are converted into absolute paths first.
+func isInside(dir, file string) bool {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(absDir, absFile)
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// addDependencies registers an output file in the dependency graph. The
+// output depends on the given template including the base template and
+// all partials, and on the given source files.
+func (w *writer) addDependencies(output, tplName string, sources []string) {
+	if w.ctx.Graph == nil {
+		return
+	}
+
+	files, _ := tpl.Files(w.templatesDir(), w.templatePath(tplName))
+
+	for _, file := range files {
+		if tplPath, err := filepath.Abs(file); err == nil {
+			w.ctx.Graph.Add(graph.Template, tplPath, output)
+			w.addAffected(tplPath, output)
+		}
+	}
+
+	for _, source := range sources {
+		if source != "" {
+			w.ctx.Graph.Add(graph.Source, source, output)
+			w.addAffected(source, output)
+		}
+	}
+}
+
+// addAffected marks an output as affected in a partial build if the
+// given file it depends on has changed.
+func (w *writer) addAffected(file, output string) {
+	if w.changed[file] {
+		w.affected[output] = true
+	}
+}
+
+// templateName determines the template for a page of the given section,
+// like blog. The template is looked up in the following order:
+//
+//  1. The template from the page's Template front matter key.
+//  2. The template of the page's type.
+//  3. A section-specific template for each of the given kinds, like
+//     blog/page.html.
+//  4. The default template for each of the given kinds, like page.html.
+//
+// The kinds are templates like taxonomy.html or list-page.html in order
+// of precedence. If no template exists, the last kind is returned.
+func (w *writer) templateName(page *model.Page, section string, kinds ...string) (string, error) {
+	switch val := page.Param(templateKey).(type) {
+	case nil:
+	case string:
+		return val, nil
 	default:
-		pageTpl = defaultTpl
+		return "", fmt.Errorf("%s: invalid value for key %s: expected string, got %v",
+			page.SourcePath(), templateKey, val)
 	}
 
-	if !w.ctx.RecompileTemplates && tpl.IsRegistered(pageTpl) {
-		return tpl.Get(pageTpl)
+	if page.Type != nil && page.Type.Template != "" {
+		return page.Type.Template, nil
 	}
 
-	tplPath := filepath.Join(theme.TemplatePath(w.ctx.Path, w.ctx.Theme), pageTpl)
+	candidates := make([]string, 0, 2*len(kinds))
 
-	return tpl.Register(pageTpl, tplPath, w.ctx.RecompileTemplates)
+	if section != "" {
+		for _, kind := range kinds {
+			candidates = append(candidates, path.Join(section, kind))
+		}
+	}
+	candidates = append(candidates, kinds...)
+
+	for _, candidate := range candidates {
+		if w.templateExists(candidate) {
+			return candidate, nil
+		}
+	}
+
+	return kinds[len(kinds)-1], nil
+}
+
+// templateExists determines whether the template with the given name
+// exists in the theme's template directory.
+func (w *writer) templateExists(name string) bool {
+	if exists, ok := w.templateFiles[name]; ok {
+		return exists
+	}
+
+	info, err := os.Stat(w.templatePath(filepath.FromSlash(name)))
+	w.templateFiles[name] = err == nil && !info.IsDir()
+
+	return w.templateFiles[name]
+}
+
+// section returns the section of the given route relative to the site's
+// language, which is its top-level directory like blog. The root route
+// doesn't have a section.
+func section(route string) string {
+	return strings.SplitN(strings.TrimPrefix(route, "/"), "/", 2)[0]
+}
+
+// loadTemplate loads the template with the given name, parsing it unless
+// it has already been parsed for the site's language. Templates parsed
+// in compatibility mode are kept separately.
+func (w *writer) loadTemplate(name string) (tpl.Template, error) {
+	key := w.site.Language.Code + ":" + name
+	if w.ctx.CompatTemplates {
+		key = "compat:" + key
+	}
+
+	if t, exists := w.templates[key]; exists && !w.ctx.RecompileTemplates {
+		return t, nil
+	}
+
+	t, err := tpl.Parse(w.templatePath(name), tpl.Options{
+		Dir:    w.templatesDir(),
+		Compat: w.ctx.CompatTemplates,
+		Funcs:  w.funcs(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	w.templates[key] = t
+
+	return t, nil
+}
+
+// funcs returns all functions available in the templates of the current
+// site, so that absURL uses the site's base URL.
+func (w *writer) funcs() template.FuncMap {
+	fm := tpl.Funcs(w.site.Meta.Base)
+	for name, fn := range w.ctx.Funcs {
+		fm[name] = fn
+	}
+	return fm
+}
+
+// templateHash returns the hash of the template file with the given name.
+func (w *writer) templateHash(name string) (string, error) {
+	if hash, exists := w.templateHashes[name]; exists {
+		return hash, nil
+	}
+
+	files, err := tpl.Files(w.templatesDir(), w.templatePath(name))
+	if err != nil {
+		return "", w.templateError(name, "", err)
+	}
+
+	// Outputs have to be rendered again when switching the template
+	// package, as values are escaped differently.
+	data := [][]byte{[]byte(strconv.FormatBool(w.ctx.CompatTemplates))}
+
+	// The base template and all partials are part of the template.
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", w.templateError(name, "", err)
+		}
+		data = append(data, []byte(file), src)
+	}
+
+	w.templateHashes[name] = cache.Hash(data...)
+
+	return w.templateHashes[name], nil
+}
+
+// templateError creates a new TemplateError for the template with the
+// given name that has been used for rendering the given output file.
+func (w *writer) templateError(name, output string, err error) *TemplateError {
+	path := w.templatePath(name)
+
+	// The error may have been caused by the base template or a partial.
+	file, line := templateLocation(err)
+	if file != "" {
+		path = w.templatePath(filepath.FromSlash(file))
+	}
+
+	return &TemplateError{
+		Template: name,
+		Path:     path,
+		Line:     line,
+		Output:   output,
+		Err:      err,
+	}
+}
+
+func (w *writer) templatePath(name string) string {
+	return filepath.Join(w.templatesDir(), name)
+}
+
+func (w *writer) templatesDir() string {
+	return theme.TemplatePath(w.ctx.Path, w.ctx.Theme)
 }
 
 func (w *writer) copyDirs() error {
@@ -189,11 +771,30 @@ func (w *writer) copyDirs() error {
 		},
 	}
 
+	// Register each copied file in the dependency graph, so that it can
+	// be copied again on its own once it changes.
+	copied := func(src, dest string) {
+		if w.ctx.Graph == nil {
+			return
+		}
+		if abs, err := filepath.Abs(src); err == nil {
+			w.ctx.Graph.Add(graph.Static, abs, dest)
+		}
+	}
+
 	for _, dir := range dirs {
-		if err := fs.CopyFromOS(w.ctx.Fs, dir.src, dir.dest, dir.fileOnly); err != nil {
+		if err := fs.CopyFromOSFunc(w.ctx.Fs, dir.src, dir.dest, dir.fileOnly, copied); err != nil {
 			return err
 		}
 	}
 
 	return nil
 }
+
+func toBytes(values []string) [][]byte {
+	b := make([][]byte, len(values))
+	for i, v := range values {
+		b[i] = []byte(v)
+	}
+	return b
+}
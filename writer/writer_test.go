@@ -1,12 +1,20 @@
 package writer
 
 import (
+	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/afero"
+	"github.com/verless/verless/builder"
+	"github.com/verless/verless/cache"
+	"github.com/verless/verless/config"
 	"github.com/verless/verless/fs"
+	"github.com/verless/verless/graph"
+	"github.com/verless/verless/model"
 	"github.com/verless/verless/test"
 	"github.com/verless/verless/theme"
 )
@@ -83,3 +91,332 @@ func setupNewWriter(fs afero.Fs) *writer {
 		RecompileTemplates: false,
 	})
 }
+
+// TestWriter_WriteChanged checks if a partial build only renders the
+// outputs that depend on a changed file.
+func TestWriter_WriteChanged(t *testing.T) {
+	memMapFs := afero.NewMemMapFs()
+	c := cache.New(memMapFs, testPath, "key")
+
+	w := New(Context{
+		Fs:        memMapFs,
+		Path:      testPath,
+		OutputDir: testOutPath,
+		Cache:     c,
+		Graph:     graph.New(),
+	})
+
+	site := func(hashes map[string]string) model.Site {
+		b := builder.New(&config.Config{})
+		for id, hash := range hashes {
+			page := model.Page{Route: "/blog", ID: id, Href: "/blog/" + id}
+			page.SetSource("/project/content/blog/"+id+".md", hash)
+			test.Ok(t, b.RegisterPage(page))
+		}
+		s, err := b.Dispatch()
+		test.Ok(t, err)
+		return s
+	}
+
+	test.Ok(t, w.Write(site(map[string]string{"espresso": "a", "cappuccino": "a"})))
+	test.Ok(t, c.Commit())
+
+	// The cappuccino page isn't rendered again because it hasn't been
+	// reported as changed, even though its hash is different.
+	changed := map[string]bool{"/project/content/blog/espresso.md": true}
+	test.Ok(t, w.WriteChanged(changed, site(map[string]string{"espresso": "b", "cappuccino": "b"})))
+
+	// The espresso page and both list pages listing it are rendered again.
+	test.Equals(t, cache.Stats{Reused: 1, Rebuilt: 3}, c.Stats())
+}
+
+// TestWriter_Prune checks if stale outputs are removed unless they are
+// located outside of the output directory.
+func TestWriter_Prune(t *testing.T) {
+	memMapFs := afero.NewMemMapFs()
+	c := cache.New(memMapFs, testPath, "key")
+
+	files := map[string]bool{
+		filepath.Join(testOutPath, "blog", indexFile): false,
+		filepath.Join("/other", indexFile):            true,
+		filepath.Join(testOutPath, "..", indexFile):   true,
+	}
+
+	for file := range files {
+		test.Ok(t, afero.WriteFile(memMapFs, file, []byte("stale"), 0644))
+		c.StoreOutput(file, "key", false)
+	}
+	test.Ok(t, c.Commit())
+
+	w := New(Context{
+		Fs:        memMapFs,
+		OutputDir: testOutPath,
+		Cache:     c,
+	})
+	test.Ok(t, w.Prune())
+
+	for file, kept := range files {
+		exists, err := afero.Exists(memMapFs, file)
+		test.Ok(t, err)
+		test.Equals(t, kept, exists)
+	}
+}
+
+// TestWriter_copyResources checks if resources are only copied if they
+// changed and if resources that aren't copied anymore are pruned.
+func TestWriter_copyResources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "verless-resources")
+	test.Ok(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	src := filepath.Join(dir, "crema.jpg")
+	test.Ok(t, ioutil.WriteFile(src, []byte("crema"), 0644))
+
+	memMapFs := afero.NewMemMapFs()
+	c := cache.New(memMapFs, testPath, "key")
+	w := New(Context{Fs: c.Fs(memMapFs), OutputDir: testOutPath, Cache: c})
+
+	pageDir := filepath.Join(testOutPath, "blog", "espresso")
+	dest := filepath.Join(pageDir, "crema.jpg")
+	resources := []model.Resource{model.NewResource("crema.jpg", "/blog/espresso/crema.jpg", src)}
+
+	test.Ok(t, w.copyResources(pageDir, resources))
+	test.Ok(t, c.Commit())
+
+	// A fresh resource isn't copied again, so a modified copy is kept.
+	test.Ok(t, afero.WriteFile(memMapFs, dest, []byte("modified"), 0644))
+	test.Ok(t, w.copyResources(pageDir, resources))
+	test.Ok(t, c.Commit())
+
+	data, err := afero.ReadFile(memMapFs, dest)
+	test.Ok(t, err)
+	test.Equals(t, "modified", string(data))
+
+	// Resources of deleted bundles are pruned.
+	test.Ok(t, w.Prune())
+
+	exists, err := afero.Exists(memMapFs, dest)
+	test.Ok(t, err)
+	test.Equals(t, false, exists)
+}
+
+// TestWriter_writeRedirects checks if the writer writes a redirect stub
+// for each alias and the redirect files in all configured formats, and
+// if all of them are recorded in the build cache.
+func TestWriter_writeRedirects(t *testing.T) {
+	memMapFs := afero.NewMemMapFs()
+	c := cache.New(memMapFs, testPath, "key")
+
+	w := New(Context{
+		Fs:              memMapFs,
+		OutputDir:       testOutPath,
+		RedirectFormats: []string{RedirectsNetlify, RedirectsNginx},
+		Cache:           c,
+	})
+	w.site = model.Site{
+		Meta: model.Meta{Base: "https://example.com/"},
+		Redirects: []model.Redirect{
+			{From: "/old-post", To: "/blog/new-post"},
+		},
+	}
+
+	test.Ok(t, w.writeRedirectStubs())
+	test.Ok(t, w.writeRedirectFiles(w.site.Redirects))
+
+	expected := map[string][]string{
+		path.Join(testOutPath, "old-post", indexFile): {
+			`<link rel="canonical" href="https://example.com/blog/new-post" />`,
+			`<meta http-equiv="refresh" content="0; url=https://example.com/blog/new-post" />`,
+		},
+		path.Join(testOutPath, "_redirects"):    {"/old-post /blog/new-post 301\n"},
+		path.Join(testOutPath, "redirects.map"): {"/old-post /blog/new-post;\n/old-post/ /blog/new-post;\n"},
+	}
+
+	for file, contents := range expected {
+		data, err := afero.ReadFile(memMapFs, file)
+		test.Ok(t, err)
+
+		for _, content := range contents {
+			test.Assert(t, strings.Contains(string(data), content), "%s should contain %s", file, content)
+		}
+	}
+
+	test.Equals(t, cache.Stats{Rebuilt: 3}, c.Stats())
+	test.Ok(t, c.Commit())
+
+	// Unchanged redirects are kept, so none of the files is stale.
+	test.Ok(t, w.writeRedirectStubs())
+	test.Ok(t, w.writeRedirectFiles(w.site.Redirects))
+	test.Equals(t, cache.Stats{Reused: 3}, c.Stats())
+	test.Equals(t, 0, len(c.Stale()))
+
+	w.ctx.RedirectFormats = []string{"apache"}
+	test.Assert(t, w.writeRedirectFiles(w.site.Redirects) != nil, "invalid formats should return an error")
+}
+
+// TestWriter_writeRedirectStub_language checks if redirect stubs are
+// written in the language of their site.
+func TestWriter_writeRedirectStub_language(t *testing.T) {
+	tests := map[string]struct {
+		language model.Language
+		expected string
+	}{
+		"single language": {
+			expected: "<html>",
+		},
+		"german": {
+			language: model.Language{Code: "de", Href: "/de"},
+			expected: `<html lang="de">`,
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		memMapFs := afero.NewMemMapFs()
+
+		w := New(Context{Fs: memMapFs, OutputDir: testOutPath})
+		w.site = model.Site{Language: testCase.language}
+
+		redirect := model.Redirect{From: testCase.language.Href + "/old-post", To: testCase.language.Href + "/new-post"}
+		test.Ok(t, w.writeRedirectStub(redirect))
+
+		data, err := afero.ReadFile(memMapFs, path.Join(testOutPath, redirect.From, indexFile))
+		test.Ok(t, err)
+		test.Assert(t, strings.Contains(string(data), testCase.expected), "stub should contain %s", testCase.expected)
+	}
+}
+
+// TestWriter_templateName checks if templates are looked up by front
+// matter, page type, section and kind in that order.
+func TestWriter_templateName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "verless-templates")
+	test.Ok(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	templates := []string{
+		theme.PageTemplate,
+		theme.ListPageTemplate,
+		theme.TaxonomyTemplate,
+		"blog/page.html",
+		"recipe.html",
+	}
+
+	for _, name := range templates {
+		file := filepath.Join(theme.TemplatePath(dir, theme.Default), filepath.FromSlash(name))
+		test.Ok(t, os.MkdirAll(filepath.Dir(file), 0755))
+		test.Ok(t, ioutil.WriteFile(file, []byte(""), 0644))
+	}
+
+	w := New(Context{Path: dir})
+
+	tests := map[string]struct {
+		page          model.Page
+		route         string
+		kinds         []string
+		expected      string
+		expectedError bool
+	}{
+		"front matter": {
+			page:     model.Page{Type: &model.Type{Template: "recipe.html"}, Params: map[string]interface{}{"template": "special.html"}},
+			route:    "/blog",
+			kinds:    []string{theme.PageTemplate},
+			expected: "special.html",
+		},
+		"invalid front matter": {
+			page:          model.Page{Params: map[string]interface{}{"Template": 1}},
+			kinds:         []string{theme.PageTemplate},
+			expectedError: true,
+		},
+		"page type": {
+			page:     model.Page{Type: &model.Type{Template: "recipe.html"}},
+			route:    "/blog",
+			kinds:    []string{theme.PageTemplate},
+			expected: "recipe.html",
+		},
+		"section": {
+			route:    "/blog/2020",
+			kinds:    []string{theme.PageTemplate},
+			expected: "blog/page.html",
+		},
+		"section without template": {
+			route:    "/recipes",
+			kinds:    []string{theme.PageTemplate},
+			expected: theme.PageTemplate,
+		},
+		"taxonomy": {
+			route:    "/tags/coffee",
+			kinds:    []string{theme.TaxonomyTemplate, theme.ListPageTemplate},
+			expected: theme.TaxonomyTemplate,
+		},
+		"list page": {
+			route:    "/",
+			kinds:    []string{theme.ListPageTemplate},
+			expected: theme.ListPageTemplate,
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		tplName, err := w.templateName(&testCase.page, section(testCase.route), testCase.kinds...)
+
+		if testCase.expectedError {
+			test.Assert(t, err != nil, "invalid template keys should return an error")
+			continue
+		}
+		test.Ok(t, err)
+		test.Equals(t, testCase.expected, tplName)
+	}
+}
+
+// TestWriter_loadTemplate checks if absURL uses the base URL of the site
+// that is being written and if additional functions are available.
+func TestWriter_loadTemplate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "verless-templates")
+	test.Ok(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	file := filepath.Join(theme.TemplatePath(dir, theme.Default), theme.PageTemplate)
+	test.Ok(t, os.MkdirAll(filepath.Dir(file), 0755))
+	test.Ok(t, ioutil.WriteFile(file, []byte(`{{absURL "/blog"}} {{shout "hi"}}`), 0644))
+
+	w := New(Context{
+		Path: dir,
+		Funcs: map[string]interface{}{
+			"shout": strings.ToUpper,
+		},
+	})
+
+	tests := map[string]struct {
+		site     model.Site
+		expected string
+	}{
+		"default language": {
+			site: model.Site{
+				Meta: model.Meta{Base: "https://example.com/"},
+			},
+			expected: "https://example.com/blog HI",
+		},
+		"language with its own base URL": {
+			site: model.Site{
+				Meta:     model.Meta{Base: "https://example.de"},
+				Language: model.Language{Code: "de"},
+			},
+			expected: "https://example.de/blog HI",
+		},
+	}
+
+	for name, testCase := range tests {
+		t.Log(name)
+
+		w.site = testCase.site
+
+		tpl, err := w.loadTemplate(theme.PageTemplate)
+		test.Ok(t, err)
+
+		var out strings.Builder
+		test.Ok(t, tpl.Execute(&out, nil))
+		test.Equals(t, testCase.expected, out.String())
+	}
+}